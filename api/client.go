@@ -8,6 +8,8 @@ import (
 	"math/big"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/NotiFansly/notifansly-bot/internal/config"
@@ -17,15 +19,21 @@ import (
 )
 
 type Client struct {
-	HTTPClient *http.Client
-	BaseURL    string
-	Token      string
-	UserAgent  string
-	DeviceID   string
-	SessionID  string
-	CheckKey   string
-	Limiter    *rate.Limiter
-	aggregator *health.Aggregator
+	HTTPClient   *http.Client
+	BaseURL      string
+	WebsocketURL string
+	WebURL       string
+	Token        string
+	UserAgent    string
+	DeviceID     string
+	SessionID    string
+	CheckKey     string
+	Limiter      *rate.Limiter
+	aggregator   *health.Aggregator
+
+	statusMu       sync.Mutex
+	throttledUntil time.Time
+	loggedOut      bool
 }
 
 type AccountInfo struct {
@@ -33,20 +41,42 @@ type AccountInfo struct {
 }
 
 type ModelAccountInfo struct {
-	ID          string `json:"id"`
-	Username    string `json:"username"`
-	DisplayName string `json:"displayName"`
-	About       string `json:"about"`
-	Avatar      struct {
-		Variants []struct {
-			Locations []struct {
-				Location string `json:"location"`
-			} `json:"locations"`
-		} `json:"variants"`
+	ID          string       `json:"id"`
+	Username    string       `json:"username"`
+	DisplayName string       `json:"displayName"`
+	About       string       `json:"about"`
+	Avatar      AccountImage `json:"avatar"`
+	Banner      AccountImage `json:"banner"`
+}
+
+// AccountImage is an account's avatar or banner. The ID changes whenever a new image is
+// uploaded, while locations are signed URLs that change on their own.
+type AccountImage struct {
+	ID       string `json:"id"`
+	Variants []struct {
 		Locations []struct {
 			Location string `json:"location"`
 		} `json:"locations"`
-	} `json:"avatar"`
+	} `json:"variants"`
+	Locations []struct {
+		Location string `json:"location"`
+	} `json:"locations"`
+}
+
+// URL returns the location of the first variant, falling back to the original image.
+func (img *AccountImage) URL() string {
+	if len(img.Variants) > 0 && len(img.Variants[0].Locations) > 0 {
+		return img.Variants[0].Locations[0].Location
+	}
+	if len(img.Locations) > 0 {
+		return img.Locations[0].Location
+	}
+	return ""
+}
+
+// AvatarURL returns the location of the account's avatar, or "" if the account has no avatar.
+func (a *ModelAccountInfo) AvatarURL() string {
+	return a.Avatar.URL()
 }
 
 type FollowingAccount struct {
@@ -63,32 +93,36 @@ type FanslyResponse struct {
 	Error   FanslyError `json:"error"`
 }
 
-func NewClient(token, userAgent string, aggregator *health.Aggregator) (*Client, error) {
+// NewClient signs in with token against the configured Fansly endpoints. ctx bounds the
+// requests made while setting up the device, session and check key.
+func NewClient(ctx context.Context, token, userAgent string, aggregator *health.Aggregator) (*Client, error) {
 	limit := rate.Limit(config.ApiRequestsPerSecond)
 	limiter := rate.NewLimiter(limit, config.ApiBurst)
 
 	client := &Client{
-		HTTPClient: &http.Client{},
-		BaseURL:    "https://apiv3.fansly.com",
-		Token:      token,
-		UserAgent:  userAgent,
-		Limiter:    limiter,
-		aggregator: aggregator,
+		HTTPClient:   &http.Client{Timeout: time.Duration(config.FanslyRequestTimeout) * time.Second},
+		BaseURL:      config.FanslyAPIBaseURL,
+		WebsocketURL: config.FanslyWebsocketURL,
+		WebURL:       config.FanslyWebURL,
+		Token:        token,
+		UserAgent:    userAgent,
+		Limiter:      limiter,
+		aggregator:   aggregator,
 	}
 
-	deviceID, err := client.getDeviceID()
+	deviceID, err := client.getDeviceID(ctx)
 	if err != nil {
 		return nil, err
 	}
 	client.DeviceID = deviceID
 
-	sessionID, err := client.getSessionID()
+	sessionID, err := client.getSessionID(ctx)
 	if err != nil {
 		return nil, err
 	}
 	client.SessionID = sessionID
 
-	checkKey, err := client.guessCheckKey()
+	checkKey, err := client.guessCheckKey(ctx)
 	if err != nil {
 		client.CheckKey = "oybZy8-fySzis-bubayf"
 	} else {
@@ -99,8 +133,19 @@ func NewClient(token, userAgent string, aggregator *health.Aggregator) (*Client,
 	return client, nil
 }
 
+// newRequest builds a request against BaseURL for the given path (including query string).
+func (c *Client) newRequest(ctx context.Context, method, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return req, nil
+}
+
+// sendRequest signs and sends req. Responses with an error status are closed and
+// reported as ErrUnauthorized, ErrRateLimited, ErrNotFound or a generic status error.
 func (c *Client) sendRequest(req *http.Request) (*http.Response, error) {
-	err := c.Limiter.Wait(context.Background())
+	err := c.Limiter.Wait(req.Context())
 	if err != nil {
 		return nil, fmt.Errorf("rate limiter wait error: %w", err)
 	}
@@ -111,8 +156,8 @@ func (c *Client) sendRequest(req *http.Request) (*http.Response, error) {
 		"fansly-client-id":    c.DeviceID,
 		"fansly-client-ts":    fmt.Sprintf("%d", getClientTimestamp()),
 		"fansly-session-id":   c.SessionID,
-		"origin":              "https://fansly.com",
-		"referer":             "https://fansly.com/",
+		"origin":              c.WebURL,
+		"referer":             c.WebURL + "/",
 		"user-agent":          c.UserAgent,
 	}
 
@@ -131,20 +176,70 @@ func (c *Client) sendRequest(req *http.Request) (*http.Response, error) {
 	if c.aggregator != nil {
 		c.aggregator.RecordCall(success)
 	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	c.recordStatus(resp)
+
+	if statusErr := statusError(resp); statusErr != nil {
+		resp.Body.Close()
+		return nil, statusErr
+	}
 
-	return resp, err
+	return resp, nil
+}
+
+func statusError(resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode >= 400:
+		return fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// recordStatus tracks whether the account behind this client is throttled or logged out,
+// so a Pool can route requests to other accounts in the meantime.
+func (c *Client) recordStatus(resp *http.Response) {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		cooldown := time.Minute
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			cooldown = time.Duration(seconds) * time.Second
+		}
+		c.throttledUntil = time.Now().Add(cooldown)
+	case resp.StatusCode == http.StatusUnauthorized:
+		c.loggedOut = true
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		c.loggedOut = false
+	}
+}
+
+// Available reports whether the client is neither throttled nor logged out.
+func (c *Client) Available() bool {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+	return !c.loggedOut && time.Now().After(c.throttledUntil)
 }
 
 func (c *Client) GetMyAccountInfo() (*AccountInfo, error) {
-	url := fmt.Sprintf("%s/api/v1/account/me?ngsw-bypass=true", c.BaseURL)
-	req, err := http.NewRequest("GET", url, nil)
+	return c.GetMyAccountInfoContext(context.Background())
+}
+
+func (c *Client) GetMyAccountInfoContext(ctx context.Context) (*AccountInfo, error) {
+	req, err := c.newRequest(ctx, "GET", "/api/v1/account/me?ngsw-bypass=true")
 	if err != nil {
 		return nil, err
 	}
 
-	//req.Header.Set("Authorization", c.Token)
-	//req.Header.Set("User-Agent", c.UserAgent)
-
 	resp, err := c.sendRequest(req)
 	if err != nil {
 		return nil, err
@@ -161,10 +256,7 @@ func (c *Client) GetMyAccountInfo() (*AccountInfo, error) {
 		return nil, err
 	}
 
-	//fmt.Printf("%v", resp)
-
 	if !result.Success {
-		//fmt.Printf("%v", result)
 		return nil, fmt.Errorf("failed to get account info")
 	}
 
@@ -176,8 +268,12 @@ func (c *Client) GetMyAccountInfo() (*AccountInfo, error) {
 }
 
 func (c *Client) GetFollowing(accountID string) ([]FollowingAccount, error) {
-	url := fmt.Sprintf("%s/api/v1/account/%s/following?before=0&after=0&limit=999&offset=0", c.BaseURL, accountID)
-	req, err := http.NewRequest("GET", url, nil)
+	return c.GetFollowingContext(context.Background(), accountID)
+}
+
+func (c *Client) GetFollowingContext(ctx context.Context, accountID string) ([]FollowingAccount, error) {
+	path := fmt.Sprintf("/api/v1/account/%s/following?before=0&after=0&limit=999&offset=0", accountID)
+	req, err := c.newRequest(ctx, "GET", path)
 	if err != nil {
 		return nil, err
 	}
@@ -197,7 +293,6 @@ func (c *Client) GetFollowing(accountID string) ([]FollowingAccount, error) {
 	}
 
 	if !result.Success {
-		//fmt.Printf("%v", url)
 		return nil, fmt.Errorf("failed to get following list")
 	}
 
@@ -205,9 +300,12 @@ func (c *Client) GetFollowing(accountID string) ([]FollowingAccount, error) {
 }
 
 func (c *Client) FollowAccount(modelID string) error {
-	url := fmt.Sprintf("%s/api/v1/account/%s/followers?ngsw-bypass=true", c.BaseURL, modelID)
-	//fmt.Printf("[FollowAccount] URL: %v\n", url)
-	req, err := http.NewRequest("POST", url, nil)
+	return c.FollowAccountContext(context.Background(), modelID)
+}
+
+func (c *Client) FollowAccountContext(ctx context.Context, modelID string) error {
+	path := fmt.Sprintf("/api/v1/account/%s/followers?ngsw-bypass=true", modelID)
+	req, err := c.newRequest(ctx, "POST", path)
 	if err != nil {
 		return err
 	}
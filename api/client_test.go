@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+// newTestClient returns a client that sends every request to handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &Client{
+		HTTPClient: server.Client(),
+		BaseURL:    server.URL,
+		WebURL:     server.URL,
+		Token:      "token",
+		Limiter:    rate.NewLimiter(rate.Inf, 1),
+	}
+}
+
+func TestSendRequestStatusErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		retryAfter    string
+		wantErr       error
+		wantAnyErr    bool
+		wantAvailable bool
+	}{
+		{name: "success", status: http.StatusOK, wantAvailable: true},
+		{name: "unauthorized", status: http.StatusUnauthorized, wantErr: ErrUnauthorized},
+		{name: "rate limited", status: http.StatusTooManyRequests, retryAfter: "30", wantErr: ErrRateLimited},
+		{name: "not found", status: http.StatusNotFound, wantErr: ErrNotFound, wantAvailable: true},
+		{name: "server error", status: http.StatusInternalServerError, wantAnyErr: true, wantAvailable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("authorization") != "token" {
+					t.Errorf("authorization header = %q, want the client token", r.Header.Get("authorization"))
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"success":true,"response":{"stream":{"status":2,"viewerCount":5}}}`))
+			})
+
+			info, err := client.GetStreamInfoContext(context.Background(), "1")
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantAnyErr:
+				if err == nil {
+					t.Error("expected an error")
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case info.Response.Stream.ViewerCount != 5:
+				t.Errorf("viewer count = %d, want 5", info.Response.Stream.ViewerCount)
+			}
+
+			if got := client.Available(); got != tt.wantAvailable {
+				t.Errorf("Available() = %v, want %v", got, tt.wantAvailable)
+			}
+		})
+	}
+}
+
+func TestGetDeviceIDStatusErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr error
+	}{
+		{name: "success", status: http.StatusOK, body: `{"success":true,"response":"device"}`, want: "device"},
+		{name: "unauthorized", status: http.StatusUnauthorized, body: `unauthorized`, wantErr: ErrUnauthorized},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: `slow down`, wantErr: ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/device/id" {
+					t.Errorf("path = %q, want /api/v1/device/id", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			got, err := client.getDeviceID(context.Background())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("device ID = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
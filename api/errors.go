@@ -0,0 +1,15 @@
+package api
+
+import "errors"
+
+var (
+	// ErrNoTimelineAccess is returned when the account cannot read a creator's timeline,
+	// usually because the timeline requires following or subscribing.
+	ErrNoTimelineAccess = errors.New("no timeline access")
+	// ErrNotFound is returned when the requested account, post or resource does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized is returned when the Fansly token was rejected (e.g. logged out).
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited is returned when Fansly throttled the request.
+	ErrRateLimited = errors.New("rate limited")
+)
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"net/url"
+	"strings"
 )
 
 //type AccountInfo struct {
@@ -13,9 +15,11 @@ import (
 //}
 
 func (c *Client) GetAccountInfo(username string) (*ModelAccountInfo, error) {
-	url := fmt.Sprintf("%s/api/v1/account?usernames=%s&ngsw-bypass=true", c.BaseURL, username)
-	//fmt.Printf("Creator Request Url: %v\n", url)
-	req, err := http.NewRequest("GET", url, nil)
+	return c.GetAccountInfoContext(context.Background(), username)
+}
+
+func (c *Client) GetAccountInfoContext(ctx context.Context, username string) (*ModelAccountInfo, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/api/v1/account?usernames=%s&ngsw-bypass=true", url.QueryEscape(username)))
 	if err != nil {
 		return nil, err
 	}
@@ -26,46 +30,42 @@ func (c *Client) GetAccountInfo(username string) (*ModelAccountInfo, error) {
 	}
 	defer resp.Body.Close()
 
-	//fmt.Printf("Creator Response: %v", resp)
-
 	var result struct {
 		Success  bool               `json:"success"`
 		Response []ModelAccountInfo `json:"response"`
+		Error    *FanslyError       `json:"error,omitempty"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 
-	if !result.Success || len(result.Response) == 0 {
-		return nil, fmt.Errorf("failed to get account info for %s", username)
+	if !result.Success {
+		return nil, fanslyError(result.Error)
 	}
 
 	if len(result.Response) == 0 {
-		return nil, fmt.Errorf("no account info found for %s", username)
+		return nil, fmt.Errorf("%w: no account info found for %s", ErrNotFound, username)
 	}
 
 	return &result.Response[0], nil
 }
 
 func (c *Client) GetAccountInfoByID(fanslyId string) (*ModelAccountInfo, error) {
-	url := fmt.Sprintf("%s/api/v1/account?ids=%s&ngsw-bypass=true", c.BaseURL, fanslyId)
+	return c.GetAccountInfoByIDContext(context.Background(), fanslyId)
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+func (c *Client) GetAccountInfoByIDContext(ctx context.Context, fanslyId string) (*ModelAccountInfo, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/api/v1/account?ids=%s&ngsw-bypass=true", url.QueryEscape(fanslyId)))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, err
 	}
 
 	resp, err := c.sendRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// Check HTTP status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
-	}
-
 	var result struct {
 		Success  bool               `json:"success"`
 		Response []ModelAccountInfo `json:"response"`
@@ -73,19 +73,83 @@ func (c *Client) GetAccountInfoByID(fanslyId string) (*ModelAccountInfo, error)
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if !result.Success {
-		if result.Error != nil {
-			return nil, fmt.Errorf("API error (code %d): %s", result.Error.Code, result.Error.Details)
-		}
-		return nil, fmt.Errorf("API request failed without error details")
+		return nil, fanslyError(result.Error)
 	}
 
 	if len(result.Response) == 0 {
-		return nil, fmt.Errorf("no account info found for ID %s", fanslyId)
+		return nil, fmt.Errorf("%w: no account info found for ID %s", ErrNotFound, fanslyId)
 	}
 
 	return &result.Response[0], nil
 }
+
+// MaxAccountsPerRequest is the largest number of IDs or usernames sent in one batch lookup.
+const MaxAccountsPerRequest = 50
+
+// GetAccountsByIDs looks up several accounts in a single request. IDs that do not exist are
+// simply missing from the result.
+func (c *Client) GetAccountsByIDs(ids []string) ([]ModelAccountInfo, error) {
+	return c.GetAccountsByIDsContext(context.Background(), ids)
+}
+
+func (c *Client) GetAccountsByIDsContext(ctx context.Context, ids []string) ([]ModelAccountInfo, error) {
+	return c.getAccounts(ctx, "ids", ids)
+}
+
+// GetAccountsByUsernames looks up several accounts by username in a single request.
+func (c *Client) GetAccountsByUsernames(usernames []string) ([]ModelAccountInfo, error) {
+	return c.GetAccountsByUsernamesContext(context.Background(), usernames)
+}
+
+func (c *Client) GetAccountsByUsernamesContext(ctx context.Context, usernames []string) ([]ModelAccountInfo, error) {
+	return c.getAccounts(ctx, "usernames", usernames)
+}
+
+func (c *Client) getAccounts(ctx context.Context, param string, values []string) ([]ModelAccountInfo, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = url.QueryEscape(v)
+	}
+
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/api/v1/account?%s=%s&ngsw-bypass=true", param, strings.Join(escaped, ",")))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success  bool               `json:"success"`
+		Response []ModelAccountInfo `json:"response"`
+		Error    *FanslyError       `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !result.Success {
+		return nil, fanslyError(result.Error)
+	}
+
+	return result.Response, nil
+}
+
+// fanslyError converts the error payload of an unsuccessful response into a Go error.
+func fanslyError(apiErr *FanslyError) error {
+	if apiErr == nil {
+		return fmt.Errorf("API request failed without error details")
+	}
+	return fmt.Errorf("API error (code %d): %s", apiErr.Code, apiErr.Details)
+}
@@ -1,9 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	//"time"
 )
 
@@ -60,33 +60,31 @@ type PostResponse struct {
 	} `json:"response"`
 }
 
-func (c *Client) GetPostMedia(postID, authToken, userAgent string) ([]AccountMedia, error) {
-	url := fmt.Sprintf("https://apiv3.fansly.com/api/v1/post?ids=%s&ngsw-bypass=true", postID)
+func (c *Client) GetPostMedia(postID string) ([]AccountMedia, error) {
+	return c.GetPostMediaContext(context.Background(), postID)
+}
 
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
+func (c *Client) GetPostMediaContext(ctx context.Context, postID string) ([]AccountMedia, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/api/v1/post?ids=%s&ngsw-bypass=true", postID))
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Authorization", authToken)
-	req.Header.Add("User-Agent", userAgent)
-
-	resp, err := client.Do(req)
+	resp, err := c.sendRequest(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch post with status code %d", resp.StatusCode)
-	}
-
 	var postResp PostResponse
 	err = json.NewDecoder(resp.Body).Decode(&postResp)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(postResp.Response.Posts) == 0 {
+		return nil, fmt.Errorf("%w: post %s", ErrNotFound, postID)
+	}
+
 	return postResp.Response.AccountMedia, nil
 }
@@ -1,9 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	//"time"
 )
 
@@ -22,21 +22,24 @@ type StreamResponse struct {
 }
 
 func (c *Client) GetStreamInfo(modelID string) (*StreamResponse, error) {
-	url := fmt.Sprintf("https://apiv3.fansly.com/api/v1/streaming/channel/%s", modelID)
-	req, err := http.NewRequest("GET", url, nil)
+	return c.GetStreamInfoContext(context.Background(), modelID)
+}
+
+func (c *Client) GetStreamInfoContext(ctx context.Context, modelID string) (*StreamResponse, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/api/v1/streaming/channel/%s", modelID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, err
 	}
 
 	resp, err := c.sendRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var streamResp StreamResponse
 	if err := json.NewDecoder(resp.Body).Decode(&streamResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	/*
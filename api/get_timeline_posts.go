@@ -1,10 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
-	//"time"
 )
 
 type Post struct {
@@ -51,21 +50,11 @@ func hasTimelineAccess(response TimelineResponse) bool {
 }
 
 func (c *Client) GetTimelinePost(modelID string) ([]Post, error) {
-	before := "0"
-	url := fmt.Sprintf("https://apiv3.fansly.com/api/v1/timelinenew/%s?before=%s&after=0&wallId&contentSearch&ngsw-bypass=true", modelID, before)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	resp, err := c.sendRequest(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
+	return c.GetTimelinePostContext(context.Background(), modelID)
+}
 
-	var timelineResp TimelineResponse
-	err = json.NewDecoder(resp.Body).Decode(&timelineResp)
+func (c *Client) GetTimelinePostContext(ctx context.Context, modelID string) ([]Post, error) {
+	timelineResp, _, err := c.getTimelinePostsBatch(ctx, modelID, "0")
 	if err != nil {
 		return nil, err
 	}
@@ -73,64 +62,37 @@ func (c *Client) GetTimelinePost(modelID string) ([]Post, error) {
 	//fmt.Printf("[INFO] [ %s Response ]: %v", modelID, timelineResp)
 
 	if !hasTimelineAccess(timelineResp) {
-		return nil, fmt.Errorf("no timeline access for user %s", modelID)
+		return nil, fmt.Errorf("%w for user %s", ErrNoTimelineAccess, modelID)
 	}
 
-	/*
-		isLive := streamResp.Success &&
-			streamResp.Response.Stream.Status == 2 &&
-			//streamResp.Response.Stream.Access &&
-			time.Now().UnixMilli()-streamResp.Response.Stream.LastFetchedAt < 5*60*1000 // Last fetched within 5 minutes
-	*/
-
 	return timelineResp.Response.Posts, nil
 }
 
-func getTimelinePostsBatch(modelId, before string, authToken string, userAgent string) (TimelineResponse, string, error) {
-	headerMap := map[string]string{
-		"Authorization": authToken,
-		"User-Agent":    userAgent,
-	}
-	client := &http.Client{}
-	url := fmt.Sprintf("https://apiv3.fansly.com/api/v1/timelinenew/%s?before=%s&after=0&wallId&contentSearch&ngsw-bypass=true", modelId, before)
-	req, err := http.NewRequest("GET", url, nil)
+func (c *Client) getTimelinePostsBatch(ctx context.Context, modelID, before string) (TimelineResponse, string, error) {
+	path := fmt.Sprintf("/api/v1/timelinenew/%s?before=%s&after=0&wallId&contentSearch&ngsw-bypass=true", modelID, before)
+	req, err := c.newRequest(ctx, "GET", path)
 	if err != nil {
 		return TimelineResponse{}, "", err
 	}
 
-	//headers.AddHeadersToRequest(req, true)
-	for key, value := range headerMap {
-		req.Header.Add(key, value)
-	}
-
-	resp, err := client.Do(req)
+	resp, err := c.sendRequest(req)
 	if err != nil {
 		return TimelineResponse{}, "", err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return TimelineResponse{}, "", fmt.Errorf("failed to fetch model timeline with status code %d", resp.StatusCode)
-	}
-
 	var timelineResp TimelineResponse
 	err = json.NewDecoder(resp.Body).Decode(&timelineResp)
 	if err != nil {
 		return TimelineResponse{}, "", err
 	}
 
-	//if timelineResp.Response.AccountTimelineReadPermissionFlags.Flags == 0 {
-	//    return nil, "", fmt.Errorf("not subscribed: unable to get timeline feed")
-	//}
-
 	if len(timelineResp.Response.Posts) == 0 {
 		return timelineResp, "", nil
 	}
 
-	//nextBefore := posts[len(posts)-1].ID
 	nextBefore := timelineResp.Response.Posts[len(timelineResp.Response.Posts)-1].ID
 	//log.Printf("[Timeline Batch] Last Post Id in resposne: %v", nextBefore)
 
 	return timelineResp, nextBefore, nil
-
 }
@@ -0,0 +1,324 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/NotiFansly/notifansly-bot/internal/health"
+)
+
+// ErrNoAvailableClient is returned when every account in the pool is throttled or logged out.
+var ErrNoAvailableClient = errors.New("no fansly account available")
+
+// Pool manages several authenticated Fansly clients and spreads creators across them.
+// Every client keeps its own device/session/check key and rate limiter, so each
+// additional account adds its own request budget.
+type Pool struct {
+	mu          sync.Mutex
+	members     []*poolMember
+	assignments map[string]*poolMember // creator ID -> account handling it
+	accountIDs  map[string]string      // lowercase username -> creator ID, learned from lookups
+	next        int                    // round-robin cursor for requests not tied to a creator
+}
+
+type poolMember struct {
+	index     int
+	client    *Client
+	accountID string
+	following map[string]bool
+	assigned  int
+}
+
+func (m *poolMember) label() string {
+	if m.accountID != "" {
+		return fmt.Sprintf("#%d (%s)", m.index+1, m.accountID)
+	}
+	return fmt.Sprintf("#%d", m.index+1)
+}
+
+// NewPool creates a client for every token. Tokens that fail to initialize are skipped;
+// an error is only returned if no account could be set up at all.
+func NewPool(ctx context.Context, tokens []string, userAgent string, aggregator *health.Aggregator) (*Pool, error) {
+	pool := &Pool{
+		assignments: make(map[string]*poolMember),
+		accountIDs:  make(map[string]string),
+	}
+
+	for idx, token := range tokens {
+		client, err := NewClient(ctx, token, userAgent, aggregator)
+		if err != nil {
+			log.Printf("[Pool] Failed to initialize Fansly account #%d: %v", idx+1, err)
+			continue
+		}
+		pool.members = append(pool.members, &poolMember{
+			index:     idx,
+			client:    client,
+			following: make(map[string]bool),
+		})
+	}
+
+	if len(pool.members) == 0 {
+		return nil, fmt.Errorf("failed to initialize any of the %d configured Fansly accounts", len(tokens))
+	}
+
+	pool.RefreshFollowing(ctx)
+	log.Printf("[Pool] Initialized %d Fansly account(s)", len(pool.members))
+
+	return pool, nil
+}
+
+// Size returns the number of accounts in the pool.
+func (p *Pool) Size() int {
+	return len(p.members)
+}
+
+// RefreshFollowing reloads the following list of every account, which is used to route
+// creators whose timeline requires a follow to an account that already follows them.
+func (p *Pool) RefreshFollowing(ctx context.Context) {
+	for _, m := range p.members {
+		me, err := m.client.GetMyAccountInfoContext(ctx)
+		if err != nil || me.ID == "" {
+			log.Printf("[Pool] Could not load account info for account %s: %v", m.label(), err)
+			continue
+		}
+		following, err := m.client.GetFollowingContext(ctx, me.ID)
+		if err != nil {
+			log.Printf("[Pool] Could not load following list for account %s: %v", m.label(), err)
+			continue
+		}
+
+		set := make(map[string]bool, len(following))
+		for _, f := range following {
+			set[f.AccountID] = true
+		}
+
+		p.mu.Lock()
+		m.accountID = me.ID
+		m.following = set
+		p.mu.Unlock()
+	}
+}
+
+// pick chooses the account that should handle a request for modelID, skipping accounts
+// already tried. Accounts following the creator win, then the creator's current
+// assignment, then the least loaded account. An empty modelID is served round-robin.
+func (p *Pool) pick(modelID string, tried map[*poolMember]bool) *poolMember {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	usable := func(m *poolMember) bool {
+		return m != nil && !tried[m] && m.client.Available()
+	}
+
+	if modelID == "" {
+		for range p.members {
+			m := p.members[p.next%len(p.members)]
+			p.next++
+			if usable(m) {
+				return m
+			}
+		}
+		return nil
+	}
+
+	current := p.assignments[modelID]
+
+	var chosen *poolMember
+	if usable(current) && current.following[modelID] {
+		chosen = current
+	}
+	if chosen == nil {
+		for _, m := range p.members {
+			if usable(m) && m.following[modelID] && (chosen == nil || m.assigned < chosen.assigned) {
+				chosen = m
+			}
+		}
+	}
+	if chosen == nil && usable(current) {
+		chosen = current
+	}
+	if chosen == nil {
+		for _, m := range p.members {
+			if usable(m) && (chosen == nil || m.assigned < chosen.assigned) {
+				chosen = m
+			}
+		}
+	}
+
+	if chosen != nil && chosen != current {
+		if current != nil {
+			current.assigned--
+		}
+		chosen.assigned++
+		p.assignments[modelID] = chosen
+	}
+
+	return chosen
+}
+
+// do runs call against the account responsible for modelID and fails over to the next
+// account when the request was throttled or rejected as unauthorized.
+func (p *Pool) do(ctx context.Context, modelID string, call func(c *Client) error) error {
+	tried := make(map[*poolMember]bool)
+	var lastErr error
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		m := p.pick(modelID, tried)
+		if m == nil {
+			if lastErr != nil {
+				return lastErr
+			}
+			return ErrNoAvailableClient
+		}
+		tried[m] = true
+
+		err := call(m.client)
+		if !errors.Is(err, ErrRateLimited) && !errors.Is(err, ErrUnauthorized) {
+			return err
+		}
+
+		log.Printf("[Pool] Account %s is unavailable, failing over: %v", m.label(), err)
+		lastErr = err
+	}
+}
+
+func (p *Pool) GetStreamInfo(ctx context.Context, modelID string) (*StreamResponse, error) {
+	var result *StreamResponse
+	err := p.do(ctx, modelID, func(c *Client) error {
+		var err error
+		result, err = c.GetStreamInfoContext(ctx, modelID)
+		return err
+	})
+	return result, err
+}
+
+func (p *Pool) GetTimelinePost(ctx context.Context, modelID string) ([]Post, error) {
+	var result []Post
+	err := p.do(ctx, modelID, func(c *Client) error {
+		var err error
+		result, err = c.GetTimelinePostContext(ctx, modelID)
+		return err
+	})
+	return result, err
+}
+
+// GetAccountInfo looks up a creator by username. Creators the pool has seen before are looked
+// up with the account assigned to them; unknown usernames are served round-robin.
+func (p *Pool) GetAccountInfo(ctx context.Context, username string) (*ModelAccountInfo, error) {
+	p.mu.Lock()
+	modelID := p.accountIDs[strings.ToLower(username)]
+	p.mu.Unlock()
+
+	var result *ModelAccountInfo
+	err := p.do(ctx, modelID, func(c *Client) error {
+		var err error
+		result, err = c.GetAccountInfoContext(ctx, username)
+		return err
+	})
+	if err == nil && result != nil {
+		p.rememberAccounts(*result)
+	}
+	return result, err
+}
+
+func (p *Pool) GetAccountInfoByID(ctx context.Context, fanslyId string) (*ModelAccountInfo, error) {
+	var result *ModelAccountInfo
+	err := p.do(ctx, fanslyId, func(c *Client) error {
+		var err error
+		result, err = c.GetAccountInfoByIDContext(ctx, fanslyId)
+		return err
+	})
+	if err == nil && result != nil {
+		p.rememberAccounts(*result)
+	}
+	return result, err
+}
+
+// rememberAccounts records the creator IDs behind usernames, so later lookups by username can
+// use the creator's assigned account.
+func (p *Pool) rememberAccounts(accounts ...ModelAccountInfo) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, account := range accounts {
+		if account.ID != "" && account.Username != "" {
+			p.accountIDs[strings.ToLower(account.Username)] = account.ID
+		}
+	}
+}
+
+// GetAccountsByIDs looks up the given accounts in chunks of MaxAccountsPerRequest,
+// spreading the chunks across the accounts in the pool.
+func (p *Pool) GetAccountsByIDs(ctx context.Context, ids []string) ([]ModelAccountInfo, error) {
+	return p.getAccountsChunked(ctx, ids, (*Client).GetAccountsByIDsContext)
+}
+
+// GetAccountsByUsernames looks up the given usernames in chunks of MaxAccountsPerRequest.
+func (p *Pool) GetAccountsByUsernames(ctx context.Context, usernames []string) ([]ModelAccountInfo, error) {
+	return p.getAccountsChunked(ctx, usernames, (*Client).GetAccountsByUsernamesContext)
+}
+
+func (p *Pool) getAccountsChunked(ctx context.Context, values []string, lookup func(*Client, context.Context, []string) ([]ModelAccountInfo, error)) ([]ModelAccountInfo, error) {
+	var accounts []ModelAccountInfo
+	for start := 0; start < len(values); start += MaxAccountsPerRequest {
+		chunk := values[start:min(start+MaxAccountsPerRequest, len(values))]
+		err := p.do(ctx, "", func(c *Client) error {
+			result, err := lookup(c, ctx, chunk)
+			if err != nil {
+				return err
+			}
+			p.rememberAccounts(result...)
+			accounts = append(accounts, result...)
+			return nil
+		})
+		if err != nil {
+			return accounts, err
+		}
+	}
+	return accounts, nil
+}
+
+// EnsureFollowing makes sure at least one account in the pool follows modelID, following
+// the creator with its assigned account if none does yet.
+func (p *Pool) EnsureFollowing(ctx context.Context, modelID string) error {
+	p.mu.Lock()
+	for _, m := range p.members {
+		if m.following[modelID] {
+			p.mu.Unlock()
+			return nil
+		}
+	}
+	p.mu.Unlock()
+
+	var follower *Client
+	err := p.do(ctx, modelID, func(c *Client) error {
+		follower = c
+		return c.FollowAccountContext(ctx, modelID)
+	})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, m := range p.members {
+		if m.client == follower {
+			m.following[modelID] = true
+			if current := p.assignments[modelID]; current != m {
+				if current != nil {
+					current.assigned--
+				}
+				m.assigned++
+				p.assignments[modelID] = m
+			}
+			break
+		}
+	}
+	return nil
+}
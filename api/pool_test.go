@@ -0,0 +1,294 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+// stubMember returns a pool member that follows the given creators.
+func stubMember(index, assigned int, following ...string) *poolMember {
+	member := &poolMember{index: index, client: &Client{}, following: make(map[string]bool), assigned: assigned}
+	for _, id := range following {
+		member.following[id] = true
+	}
+	return member
+}
+
+func newStubPool(members ...*poolMember) *Pool {
+	return &Pool{members: members, assignments: make(map[string]*poolMember), accountIDs: make(map[string]string)}
+}
+
+func TestPoolPick(t *testing.T) {
+	tests := []struct {
+		name         string
+		members      []*poolMember
+		current      int // index of the creator's current assignment, -1 for none
+		unavailable  []int
+		tried        []int
+		want         int // index of the chosen member, -1 for none
+		wantAssigned []int
+	}{
+		{
+			name:         "least loaded without assignment",
+			members:      []*poolMember{stubMember(0, 3), stubMember(1, 1), stubMember(2, 2)},
+			current:      -1,
+			want:         1,
+			wantAssigned: []int{3, 2, 2},
+		},
+		{
+			name:         "current assignment is sticky",
+			members:      []*poolMember{stubMember(0, 5), stubMember(1, 0)},
+			current:      0,
+			want:         0,
+			wantAssigned: []int{5, 0},
+		},
+		{
+			name:         "follower wins over current assignment",
+			members:      []*poolMember{stubMember(0, 1), stubMember(1, 4, "creator")},
+			current:      0,
+			want:         1,
+			wantAssigned: []int{0, 5},
+		},
+		{
+			name:         "least loaded follower",
+			members:      []*poolMember{stubMember(0, 4, "creator"), stubMember(1, 2, "creator"), stubMember(2, 0)},
+			current:      -1,
+			want:         1,
+			wantAssigned: []int{4, 3, 0},
+		},
+		{
+			name:         "unavailable assignment moves to least loaded",
+			members:      []*poolMember{stubMember(0, 2), stubMember(1, 3), stubMember(2, 1)},
+			current:      0,
+			unavailable:  []int{0},
+			want:         2,
+			wantAssigned: []int{1, 3, 2},
+		},
+		{
+			name:         "unavailable follower falls back to assignment",
+			members:      []*poolMember{stubMember(0, 1, "creator"), stubMember(1, 1)},
+			current:      1,
+			unavailable:  []int{0},
+			want:         1,
+			wantAssigned: []int{1, 1},
+		},
+		{
+			name:         "tried accounts are skipped",
+			members:      []*poolMember{stubMember(0, 1), stubMember(1, 1)},
+			current:      0,
+			tried:        []int{0},
+			want:         1,
+			wantAssigned: []int{0, 2},
+		},
+		{
+			name:         "no account available",
+			members:      []*poolMember{stubMember(0, 1), stubMember(1, 0)},
+			current:      0,
+			unavailable:  []int{0, 1},
+			want:         -1,
+			wantAssigned: []int{1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newStubPool(tt.members...)
+			if tt.current >= 0 {
+				pool.assignments["creator"] = tt.members[tt.current]
+			}
+			for _, idx := range tt.unavailable {
+				tt.members[idx].client.loggedOut = true
+			}
+			tried := make(map[*poolMember]bool)
+			for _, idx := range tt.tried {
+				tried[tt.members[idx]] = true
+			}
+
+			got := pool.pick("creator", tried)
+			if tt.want < 0 {
+				if got != nil {
+					t.Fatalf("picked %s, want none", got.label())
+				}
+			} else if got != tt.members[tt.want] {
+				t.Fatalf("picked %v, want #%d", got, tt.want+1)
+			}
+			if got != nil && pool.assignments["creator"] != got {
+				t.Errorf("creator is assigned to %s, want %s", pool.assignments["creator"].label(), got.label())
+			}
+			for idx, member := range tt.members {
+				if member.assigned != tt.wantAssigned[idx] {
+					t.Errorf("member #%d has %d creators, want %d", idx+1, member.assigned, tt.wantAssigned[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestPoolPickRoundRobin(t *testing.T) {
+	members := []*poolMember{stubMember(0, 0), stubMember(1, 0), stubMember(2, 0)}
+	members[1].client.throttledUntil = time.Now().Add(time.Minute)
+	pool := newStubPool(members...)
+
+	var got []int
+	for range 4 {
+		got = append(got, pool.pick("", nil).index)
+	}
+	want := []int{0, 2, 0, 2}
+	for idx := range want {
+		if got[idx] != want[idx] {
+			t.Fatalf("picked %v, want %v", got, want)
+		}
+	}
+	if len(pool.assignments) != 0 {
+		t.Errorf("round-robin requests created assignments: %v", pool.assignments)
+	}
+}
+
+func TestPoolFailover(t *testing.T) {
+	errOther := errors.New("boom")
+
+	tests := []struct {
+		name      string
+		results   []error // result of the call on each member
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "first account succeeds", results: []error{nil, nil}, wantCalls: 1},
+		{name: "rate limited fails over", results: []error{ErrRateLimited, nil}, wantCalls: 2},
+		{name: "unauthorized fails over", results: []error{ErrUnauthorized, nil}, wantCalls: 2},
+		{name: "other errors are returned", results: []error{errOther, nil}, wantCalls: 1, wantErr: errOther},
+		{name: "every account fails", results: []error{ErrRateLimited, ErrUnauthorized}, wantCalls: 2, wantErr: ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members := make([]*poolMember, len(tt.results))
+			for idx := range members {
+				members[idx] = stubMember(idx, idx)
+			}
+			pool := newStubPool(members...)
+
+			var calls int
+			err := pool.do(context.Background(), "creator", func(c *Client) error {
+				for idx, member := range members {
+					if member.client == c {
+						calls++
+						return tt.results[idx]
+					}
+				}
+				t.Fatal("call on a client outside the pool")
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("%d calls, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+
+	t.Run("no account available", func(t *testing.T) {
+		member := stubMember(0, 0)
+		member.client.loggedOut = true
+		err := newStubPool(member).do(context.Background(), "creator", func(c *Client) error { return nil })
+		if !errors.Is(err, ErrNoAvailableClient) {
+			t.Errorf("error = %v, want %v", err, ErrNoAvailableClient)
+		}
+	})
+}
+
+func TestPoolGetAccountInfoUsesAssignedAccount(t *testing.T) {
+	hits := make([]int, 2)
+	members := make([]*poolMember, 2)
+	for idx := range members {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			hits[idx]++
+			w.Write([]byte(`{"success":true,"response":[{"id":"42","username":"Alice"}]}`))
+		})
+		members[idx] = &poolMember{index: idx, client: client, following: make(map[string]bool)}
+	}
+	pool := newStubPool(members...)
+	pool.assignments["42"] = members[1]
+	members[1].assigned = 1
+
+	// The first lookup of an unknown username is served round-robin and teaches the pool its ID.
+	if _, err := pool.GetAccountInfo(context.Background(), "alice"); err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		if _, err := pool.GetAccountInfo(context.Background(), "ALICE"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if hits[0] != 1 || hits[1] != 3 {
+		t.Errorf("requests per account = %v, want [1 3]", hits)
+	}
+}
+
+func TestPoolGetAccountsChunked(t *testing.T) {
+	values := func(count int) []string {
+		ids := make([]string, count)
+		for idx := range ids {
+			ids[idx] = fmt.Sprint(idx + 1)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name       string
+		values     []string
+		failChunk  int // index of the lookup that fails, -1 for none
+		wantChunks []int
+		wantFound  int
+	}{
+		{name: "nothing to look up", values: nil, failChunk: -1},
+		{name: "single value", values: values(1), failChunk: -1, wantChunks: []int{1}, wantFound: 1},
+		{name: "exactly one chunk", values: values(MaxAccountsPerRequest), failChunk: -1, wantChunks: []int{50}, wantFound: 50},
+		{name: "one value over a chunk", values: values(MaxAccountsPerRequest + 1), failChunk: -1, wantChunks: []int{50, 1}, wantFound: 51},
+		{name: "several chunks", values: values(120), failChunk: -1, wantChunks: []int{50, 50, 20}, wantFound: 120},
+		{name: "failing chunk keeps earlier results", values: values(120), failChunk: 1, wantChunks: []int{50, 50}, wantFound: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newStubPool(stubMember(0, 0), stubMember(1, 0))
+
+			var chunks []int
+			var seen []string
+			accounts, err := pool.getAccountsChunked(context.Background(), tt.values, func(c *Client, ctx context.Context, chunk []string) ([]ModelAccountInfo, error) {
+				chunks = append(chunks, len(chunk))
+				if len(chunks)-1 == tt.failChunk {
+					return nil, errors.New("boom")
+				}
+				seen = append(seen, chunk...)
+				result := make([]ModelAccountInfo, len(chunk))
+				for idx, id := range chunk {
+					result[idx] = ModelAccountInfo{ID: id, Username: "creator" + id}
+				}
+				return result, nil
+			})
+
+			if (err != nil) != (tt.failChunk >= 0) {
+				t.Errorf("error = %v, want an error: %v", err, tt.failChunk >= 0)
+			}
+			if !slices.Equal(chunks, tt.wantChunks) {
+				t.Errorf("chunk sizes = %v, want %v", chunks, tt.wantChunks)
+			}
+			if len(accounts) != tt.wantFound {
+				t.Errorf("got %d accounts, want %d", len(accounts), tt.wantFound)
+			}
+			if tt.failChunk < 0 && !slices.Equal(seen, tt.values) {
+				t.Errorf("looked up %v, want %v", seen, tt.values)
+			}
+			if len(accounts) > 0 && pool.accountIDs["creator1"] != "1" {
+				t.Errorf("pool did not remember the ID of creator1: %v", pool.accountIDs)
+			}
+		})
+	}
+}
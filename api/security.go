@@ -1,22 +1,24 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/gorilla/websocket"
 	"io"
 	"net/http"
 	"regexp"
+	"time"
 	//"strings"
 )
 
-func (c *Client) getDeviceID() (string, error) {
-	req, err := http.NewRequest("GET", "https://apiv3.fansly.com/api/v1/device/id", nil)
+func (c *Client) getDeviceID(ctx context.Context) (string, error) {
+	req, err := c.newRequest(ctx, "GET", "/api/v1/device/id")
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.sendRequest(req)
 	if err != nil {
 		return "", err
 	}
@@ -39,13 +41,17 @@ func (c *Client) getDeviceID() (string, error) {
 	return result.Response, nil
 }
 
-func (c *Client) getSessionID() (string, error) {
-	wsConn, _, err := websocket.DefaultDialer.Dial("wss://wsv3.fansly.com/", nil)
+func (c *Client) getSessionID(ctx context.Context) (string, error) {
+	wsConn, _, err := websocket.DefaultDialer.DialContext(ctx, c.WebsocketURL, nil)
 	if err != nil {
 		return "", err
 	}
 	defer wsConn.Close()
 
+	if c.HTTPClient.Timeout > 0 {
+		wsConn.SetReadDeadline(time.Now().Add(c.HTTPClient.Timeout))
+	}
+
 	message := map[string]interface{}{
 		"t": 1,
 		"d": fmt.Sprintf("{\"token\":\"%s\"}", c.Token),
@@ -85,12 +91,12 @@ func (c *Client) getSessionID() (string, error) {
 	return sessionData.Session.ID, nil
 }
 
-func (c *Client) guessCheckKey() (string, error) {
+func (c *Client) guessCheckKey(ctx context.Context) (string, error) {
 	mainJSPattern := `\ssrc\s*=\s*"(main\..*?\.js)"`
 	//checkKeyPattern := `this\.checkKey_\s*=\s*\["([^"]+)","([^"]+)"\]\.reverse\(\)\.join\("-"\)\+"([^"]+)"`
 	checkKeyPattern := `let\s+i\s*=\s*\[\s*\]\s*;\s*i\.push\s*\(\s*"([^"]+)"\s*\)\s*,\s*i\.push\s*\(\s*"([^"]+)"\s*\)\s*,\s*i\.push\s*\(\s*"([^"]+)"\s*\)\s*,\s*this\.checkKey_\s*=\s*i\.join\s*\(\s*"-"\s*\)`
 
-	req, err := http.NewRequest("GET", "https://fansly.com/", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.WebURL+"/", nil)
 	if err != nil {
 		return "", err
 	}
@@ -113,8 +119,8 @@ func (c *Client) guessCheckKey() (string, error) {
 		return "", fmt.Errorf("main.js file not found")
 	}
 
-	mainJSURL := fmt.Sprintf("https://fansly.com/%s", mainJSMatch[1])
-	req, err = http.NewRequest("GET", mainJSURL, nil)
+	mainJSURL := fmt.Sprintf("%s/%s", c.WebURL, mainJSMatch[1])
+	req, err = http.NewRequestWithContext(ctx, "GET", mainJSURL, nil)
 	if err != nil {
 		return "", err
 	}
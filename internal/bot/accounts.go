@@ -0,0 +1,216 @@
+package bot
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/api"
+	"github.com/NotiFansly/notifansly-bot/internal/config"
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+)
+
+// refreshAccountsPeriodically keeps avatars, display names and usernames of all monitored
+// creators up to date using batched account lookups.
+func (b *Bot) refreshAccountsPeriodically() {
+	interval := time.Duration(config.AccountRefreshIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		b.refreshAccounts()
+
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Bot) refreshAccounts() {
+	users, err := b.Repo.GetMonitoredUsers()
+	if err != nil {
+		log.Printf("[Accounts] Error getting monitored users: %v", err)
+		return
+	}
+
+	b.accounts.addUsers(users)
+
+	userGroups := make(map[string][]models.MonitoredUser)
+	var ids []string
+	for _, user := range users {
+		if _, seen := userGroups[user.UserID]; !seen {
+			ids = append(ids, user.UserID)
+		}
+		userGroups[user.UserID] = append(userGroups[user.UserID], user)
+	}
+
+	// Creators followed through DMs are refreshed too, so DM embeds keep current names and avatars.
+	directIDs, err := b.Repo.GetDirectSubscriptionCreatorIDs()
+	if err != nil {
+		log.Printf("[Accounts] Error getting DM subscriptions: %v", err)
+	}
+	direct := make(map[string]bool, len(directIDs))
+	for _, id := range directIDs {
+		direct[id] = true
+		if _, seen := userGroups[id]; !seen {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return
+	}
+
+	accounts, err := b.APIPool.GetAccountsByIDs(b.ctx, ids)
+	if err != nil {
+		log.Printf("[Accounts] Error fetching account info (%d of %d received): %v", len(accounts), len(ids), err)
+	}
+
+	for _, account := range accounts {
+		if direct[account.ID] {
+			if err := b.Repo.UpdateDirectSubscriptionAccountInfo(account.ID, account.Username, account.AvatarURL()); err != nil {
+				log.Printf("[Accounts] Error updating DM subscriptions for %s: %v", account.Username, err)
+			}
+		}
+
+		entries, ok := userGroups[account.ID]
+		if !ok {
+			if account.Username != "" {
+				b.accounts.add(account.ID, account.Username, account.DisplayName)
+			}
+			continue
+		}
+		b.applyAccountInfo(entries, account)
+		delete(userGroups, account.ID)
+	}
+
+	// Anything left was not returned by Fansly, e.g. deleted or banned accounts.
+	if err == nil {
+		for userID, entries := range userGroups {
+			log.Printf("[Accounts] No account info returned for %s (%s); the account may no longer exist", entries[0].Username, userID)
+		}
+	}
+
+	log.Printf("[Accounts] Refreshed %d creators with %d batched lookups", len(accounts), (len(ids)+api.MaxAccountsPerRequest-1)/api.MaxAccountsPerRequest)
+}
+
+// applyAccountInfo stores fresh account info for a creator across all guilds monitoring them.
+func (b *Bot) applyAccountInfo(entries []models.MonitoredUser, account api.ModelAccountInfo) {
+	primaryUser := entries[0]
+
+	avatarLocation := account.AvatarURL()
+	if avatarLocation == "" {
+		avatarLocation = primaryUser.AvatarLocation
+	}
+	if err := b.Repo.UpdateCreatorAccountInfo(account.ID, avatarLocation, account.DisplayName); err != nil {
+		log.Printf("[Accounts] Error updating account info for %s: %v", primaryUser.Username, err)
+	}
+
+	username := primaryUser.Username
+	if account.Username != "" {
+		username = account.Username
+	}
+	b.accounts.add(account.ID, username, account.DisplayName)
+
+	if account.Username != "" && !strings.EqualFold(account.Username, primaryUser.Username) {
+		b.handleCreatorRename(entries, account.Username, avatarLocation)
+	}
+
+	b.checkProfileChanges(entries, account, avatarLocation)
+}
+
+// checkProfileChanges diffs the account against the stored profile snapshot and notifies
+// guilds that enabled profile notifications. The first snapshot of a creator is only stored.
+func (b *Bot) checkProfileChanges(entries []models.MonitoredUser, account api.ModelAccountInfo, avatarLocation string) {
+	previous, err := b.Repo.GetProfileSnapshot(account.ID)
+	if err != nil {
+		log.Printf("[Accounts] Error loading profile snapshot for %s: %v", account.Username, err)
+		return
+	}
+
+	current := &models.CreatorProfileSnapshot{
+		UserID:      account.ID,
+		DisplayName: account.DisplayName,
+		About:       account.About,
+		AvatarID:    account.Avatar.ID,
+		BannerID:    account.Banner.ID,
+		UpdatedAt:   time.Now().Unix(),
+	}
+	if err := b.Repo.SaveProfileSnapshot(current); err != nil {
+		log.Printf("[Accounts] Error saving profile snapshot for %s: %v", account.Username, err)
+	}
+
+	if previous == nil {
+		return
+	}
+
+	var changes []embed.ProfileChange
+	if previous.DisplayName != current.DisplayName {
+		changes = append(changes, embed.ProfileChange{Field: "Display Name", Old: previous.DisplayName, New: current.DisplayName})
+	}
+	if previous.About != current.About {
+		changes = append(changes, embed.ProfileChange{Field: "Bio", Old: previous.About, New: current.About})
+	}
+	if previous.AvatarID != current.AvatarID {
+		changes = append(changes, embed.ProfileChange{Field: "Avatar", Old: previous.AvatarID, New: current.AvatarID})
+	}
+	if previous.BannerID != current.BannerID {
+		changes = append(changes, embed.ProfileChange{Field: "Banner", Old: previous.BannerID, New: current.BannerID})
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	log.Printf("[Accounts] Detected %d profile change(s) for %s", len(changes), account.Username)
+	profileEmbed := embed.CreateProfileChangeEmbed(account.Username, changes, avatarLocation, account.Banner.URL())
+
+	for _, user := range entries {
+		if !user.ProfileEnabled {
+			continue
+		}
+
+		targetChannel := b.deliveryChannel(&user, deliveryPosts)
+		if _, _, err := b.sendGuildNotification(user.GuildID, user.UserID, targetChannel, "", profileEmbed, false); err != nil {
+			b.logNotificationError("profile", user, targetChannel, err)
+		}
+	}
+}
+
+// handleCreatorRename updates every guild row of a renamed creator, records the old name
+// as an alias and announces the change in guilds that opted in.
+func (b *Bot) handleCreatorRename(entries []models.MonitoredUser, newUsername, avatarLocation string) {
+	oldUsername := entries[0].Username
+	log.Printf("[Accounts] Creator %s (%s) is now known as %s", oldUsername, entries[0].UserID, newUsername)
+
+	if err := b.Repo.RenameCreator(entries[0].UserID, oldUsername, newUsername); err != nil {
+		log.Printf("[Accounts] Error renaming %s to %s: %v", oldUsername, newUsername, err)
+		return
+	}
+
+	renameEmbed := embed.CreateRenameEmbed(oldUsername, newUsername, avatarLocation)
+	for _, user := range entries {
+		user.Username = newUsername
+		b.renameForumThreads(user)
+		b.renameSubscriberRole(user)
+
+		settings, err := b.Repo.GetGuildSettings(user.GuildID)
+		if err != nil {
+			log.Printf("[Accounts] Could not fetch settings for guild %s: %v", user.GuildID, err)
+			continue
+		}
+		if !settings.AnnounceRenames {
+			continue
+		}
+
+		targetChannel := b.deliveryChannel(&user, deliveryPosts)
+		if _, _, err := b.sendGuildNotification(user.GuildID, user.UserID, targetChannel, "", renameEmbed, false); err != nil {
+			b.logNotificationError("rename", user, targetChannel, err)
+		}
+	}
+}
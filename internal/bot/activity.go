@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/charts"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+func (b *Bot) handleActivityCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	username := options[0].StringValue()
+	period := "30d"
+	if len(options) > 1 {
+		period = options[1].StringValue()
+	}
+	days := statsPeriods[period]
+
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || user == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Creator **%s** is not being monitored in this server.", username))
+		return
+	}
+
+	settings, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		settings = &models.GuildSettings{GuildID: i.GuildID}
+	}
+
+	files, err := b.activityCharts(user, days, guildLocation(settings))
+	if err != nil {
+		log.Printf("Error rendering activity charts for %s: %v", user.Username, err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error rendering activity charts: %v", err))
+		return
+	}
+	if len(files) == 0 {
+		b.editInteractionResponse(s, i, fmt.Sprintf("No activity has been recorded for **%s** in the last %d days yet.", user.Username, days))
+		return
+	}
+
+	content := fmt.Sprintf("Activity of **%s** over the last %d days.", user.Username, days)
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &content,
+		Files:   files,
+	})
+	if err != nil {
+		log.Printf("Error sending activity charts: %v", err)
+	}
+}
+
+// activityCharts renders the charts that have data: posts per day, hours live by weekday and
+// hour, and the viewers of the most recent stream.
+func (b *Bot) activityCharts(user *models.MonitoredUser, days int, loc *time.Location) ([]*discordgo.File, error) {
+	now := time.Now()
+	since := now.AddDate(0, 0, -days).Unix()
+
+	posts, err := b.Repo.GetPostEvents(user.UserID, since)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := b.Repo.GetStreamSessions(user.UserID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*discordgo.File
+	addChart := func(name string, png []byte) {
+		files = append(files, &discordgo.File{Name: name, ContentType: "image/png", Reader: bytes.NewReader(png)})
+	}
+
+	if len(posts) > 0 {
+		png, err := charts.PostsPerDay(user.Username, posts, days, loc, now)
+		if err != nil {
+			return nil, err
+		}
+		addChart("posts.png", png)
+	}
+
+	if len(sessions) > 0 {
+		png, err := charts.LiveHeatmap(user.Username, sessions, loc, now)
+		if err != nil {
+			return nil, err
+		}
+		addChart("live-hours.png", png)
+
+		latest := sessions[len(sessions)-1]
+		samples, err := b.Repo.GetStreamViewerSamples(user.UserID, latest.StartedAt)
+		if err != nil {
+			return nil, err
+		}
+		// A line needs at least two points.
+		if len(samples) > 1 {
+			png, err := charts.StreamViewers(user.Username, samples, loc)
+			if err != nil {
+				return nil, err
+			}
+			addChart("viewers.png", png)
+		}
+	}
+	return files, nil
+}
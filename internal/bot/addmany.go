@@ -0,0 +1,291 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/api"
+	"github.com/NotiFansly/notifansly-bot/internal/config"
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxBulkAdd caps how many creators a single /addmany may process.
+	maxBulkAdd = 100
+	// maxBulkFileSize caps the size of an attached username list.
+	maxBulkFileSize = 64 << 10
+	// bulkProgressInterval throttles edits of the progress message.
+	bulkProgressInterval = 2 * time.Second
+)
+
+type bulkAddStatus int
+
+const (
+	bulkAdded bulkAddStatus = iota
+	bulkLiveOnly
+	bulkAlreadyMonitored
+	bulkNotFound
+	bulkOverLimit
+	bulkFailed
+)
+
+var bulkAddStatusLabels = map[bulkAddStatus]string{
+	bulkAdded:            "✅ Added",
+	bulkLiveOnly:         "📡 Live only (timeline not accessible)",
+	bulkAlreadyMonitored: "↩️ Already monitored",
+	bulkNotFound:         "❓ Not found",
+	bulkOverLimit:        "⛔ Over limit",
+	bulkFailed:           "⚠️ Failed",
+}
+
+var bulkAddStatusOrder = []bulkAddStatus{bulkAdded, bulkLiveOnly, bulkAlreadyMonitored, bulkNotFound, bulkOverLimit, bulkFailed}
+
+func (b *Bot) handleAddManyCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+
+	var rawList, attachmentID, channelID, mentionRole string
+	for _, opt := range data.Options {
+		switch opt.Name {
+		case "usernames":
+			rawList = opt.StringValue()
+		case "file":
+			attachmentID, _ = opt.Value.(string)
+		case "channel":
+			channelID = opt.ChannelValue(s).ID
+		case "mention_role":
+			if role := opt.RoleValue(s, i.GuildID); role != nil {
+				mentionRole = role.ID
+			}
+		}
+	}
+
+	if rawList == "" && attachmentID == "" {
+		b.respondToInteraction(s, i, "Please provide a list of usernames or attach a text file with one username per line.", true)
+		return
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	go func() {
+		if attachmentID != "" {
+			attachment, ok := data.Resolved.Attachments[attachmentID]
+			if !ok {
+				b.editInteractionResponse(s, i, "Could not read the attached file.")
+				return
+			}
+			content, err := downloadAttachment(attachment)
+			if err != nil {
+				b.editInteractionResponse(s, i, fmt.Sprintf("Could not read the attached file: %v", err))
+				return
+			}
+			rawList += "\n" + content
+		}
+
+		usernames, skipped := parseUsernameList(rawList)
+		if len(usernames) == 0 {
+			b.editInteractionResponse(s, i, "No valid usernames were found in your list.")
+			return
+		}
+		if len(usernames) > maxBulkAdd {
+			b.editInteractionResponse(s, i, fmt.Sprintf("You can add at most %d creators at once; your list contains %d.", maxBulkAdd, len(usernames)))
+			return
+		}
+
+		b.runBulkAdd(s, i, usernames, skipped, channelID, mentionRole)
+	}()
+}
+
+// runBulkAdd adds every creator in usernames, editing the deferred response with progress.
+func (b *Bot) runBulkAdd(s *discordgo.Session, i *discordgo.InteractionCreate, usernames []string, skipped int, channelID, mentionRole string) {
+	results := make(map[bulkAddStatus][]string)
+	guildLimit := b.guildUserLimit(i.GuildID)
+	lastProgress := time.Time{}
+
+	for idx, username := range usernames {
+		if b.ctx.Err() != nil {
+			break
+		}
+		if time.Since(lastProgress) >= bulkProgressInterval {
+			b.editInteractionResponse(s, i, fmt.Sprintf("⏳ Adding creators... (%d/%d)\nCurrently checking **%s**\n\n%s", idx, len(usernames), username, formatBulkResults(results)))
+			lastProgress = time.Now()
+		}
+
+		status, name := b.bulkAddCreator(i.GuildID, username, channelID, mentionRole, guildLimit)
+		results[status] = append(results[status], name)
+	}
+
+	summary := fmt.Sprintf("Finished processing %d creators.", len(usernames))
+	if skipped > 0 {
+		summary += fmt.Sprintf(" %d entries that looked like tokens were ignored.", skipped)
+	}
+	b.editInteractionResponse(s, i, summary+"\n\n"+formatBulkResults(results))
+
+	added := append(append([]string{}, results[bulkAdded]...), results[bulkLiveOnly]...)
+	if len(added) > 0 {
+		b.recordAudit(s, i, fmt.Sprintf("%d creators", len(added)), "", fmt.Sprintf("%s in <#%s>", strings.Join(added, ", "), channelID))
+	}
+	b.sendBulkAddLog(s, i, len(usernames), added)
+}
+
+// bulkAddCreator adds a single creator for /addmany and reports the outcome together with
+// the creator's display name for the result list.
+func (b *Bot) bulkAddCreator(guildID, username, channelID, mentionRole string, guildLimit int) (bulkAddStatus, string) {
+	accountInfo, err := b.APIPool.GetAccountInfo(b.ctx, username)
+	if errors.Is(err, api.ErrNotFound) || (err == nil && accountInfo == nil) {
+		return bulkNotFound, username
+	}
+	if err != nil {
+		log.Printf("[AddMany] Error getting account info for %s: %v", username, err)
+		return bulkFailed, username
+	}
+	if accountInfo.Username != "" {
+		username = accountInfo.Username
+	}
+
+	existing, err := b.Repo.GetMonitoredUser(guildID, accountInfo.ID)
+	if err != nil {
+		log.Printf("[AddMany] Error checking existing entry for %s: %v", username, err)
+		return bulkFailed, username
+	}
+	if existing != nil {
+		return bulkAlreadyMonitored, username
+	}
+
+	if guildLimit > 0 {
+		count, err := b.Repo.CountMonitoredUsersForGuild(guildID)
+		if err != nil {
+			log.Printf("[AddMany] Error checking guild limit for guild %s: %v", guildID, err)
+			return bulkFailed, username
+		}
+		if count >= int64(guildLimit) {
+			return bulkOverLimit, username
+		}
+	}
+
+	_, timelineErr := b.APIPool.GetTimelinePost(b.ctx, accountInfo.ID)
+	if timelineErr != nil {
+		if followErr := b.APIPool.EnsureFollowing(b.ctx, accountInfo.ID); followErr != nil {
+			log.Printf("[AddMany] Could not automatically follow %s: %v", username, followErr)
+		}
+		_, timelineErr = b.APIPool.GetTimelinePost(b.ctx, accountInfo.ID)
+	}
+	postsEnabled := timelineErr == nil
+
+	user := newMonitoredUser(guildID, accountInfo, username, channelID, mentionRole, postsEnabled)
+	if err := b.Repo.AddOrUpdateMonitoredUser(user); err != nil {
+		log.Printf("[AddMany] Error storing %s: %v", username, err)
+		return bulkFailed, username
+	}
+	b.accounts.add(accountInfo.ID, username, accountInfo.DisplayName)
+	b.prepareDeliveryThreads(guildID, accountInfo.ID)
+
+	if !postsEnabled {
+		return bulkLiveOnly, username
+	}
+	return bulkAdded, username
+}
+
+// sendBulkAddLog posts a single summary of a bulk add to the bot owner's log channel.
+func (b *Bot) sendBulkAddLog(s *discordgo.Session, i *discordgo.InteractionCreate, requested int, added []string) {
+	if config.LogChannelID == "" {
+		return
+	}
+
+	guildName := "Unknown Server"
+	if guild, err := s.Guild(i.GuildID); err == nil {
+		guildName = guild.Name
+	}
+
+	creators := "none"
+	if len(added) > 0 {
+		creators = "`" + strings.Join(added, "`, `") + "`"
+	}
+
+	logMessage := fmt.Sprintf(
+		"`[%s]` User <@%s> (`%s`) bulk added %d of %d creators:\n**Creators:** %s\n**Server:** %s (`%s`)",
+		time.Now().Format("2006-01-02 15:04:05"),
+		i.Member.User.ID,
+		i.Member.User.Username,
+		len(added),
+		requested,
+		embed.Truncate(creators, 1500),
+		guildName,
+		i.GuildID,
+	)
+	if _, err := s.ChannelMessageSend(config.LogChannelID, logMessage); err != nil {
+		log.Printf("Failed to send log message to channel %s: %v", config.LogChannelID, err)
+	}
+}
+
+// parseUsernameList splits a list of usernames or Fansly URLs separated by whitespace or
+// commas, removing duplicates. Entries that look like tokens are dropped and counted.
+func parseUsernameList(raw string) (usernames []string, skipped int) {
+	seen := make(map[string]bool)
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
+	})
+	for _, field := range fields {
+		username := strings.TrimSpace(extractUsernameFromURL(strings.TrimSpace(field)))
+		if username == "" {
+			continue
+		}
+		if tokenRegex.MatchString(username) {
+			skipped++
+			continue
+		}
+		key := strings.ToLower(username)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		usernames = append(usernames, username)
+	}
+	return usernames, skipped
+}
+
+func downloadAttachment(attachment *discordgo.MessageAttachment) (string, error) {
+	if attachment.Size > maxBulkFileSize {
+		return "", fmt.Errorf("the file is larger than %d KB", maxBulkFileSize>>10)
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(attachment.URL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBulkFileSize))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func formatBulkResults(results map[bulkAddStatus][]string) string {
+	var sb strings.Builder
+	for _, status := range bulkAddStatusOrder {
+		names := results[status]
+		if len(names) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("**%s (%d):** %s\n", bulkAddStatusLabels[status], len(names), strings.Join(names, ", ")))
+	}
+	return embed.Truncate(sb.String(), 1800)
+}
@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// auditLogLimit caps how many entries /auditlog pages through.
+	auditLogLimit = 250
+	// maxAuditTargetLength keeps the custom IDs of the /auditlog buttons within Discord's 100 characters.
+	maxAuditTargetLength = 50
+)
+
+// recordAudit stores a configuration change made through interaction i and posts it to the
+// guild's audit channel, if one is configured. Failures are logged and never surface to the user.
+func (b *Bot) recordAudit(s *discordgo.Session, i *discordgo.InteractionCreate, target, oldValue, newValue string) {
+	if i.GuildID == "" || i.Member == nil || i.Member.User == nil {
+		return
+	}
+
+	action := ""
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		action = i.ApplicationCommandData().Name
+		if options := i.ApplicationCommandData().Options; len(options) > 0 && options[0].Type == discordgo.ApplicationCommandOptionSubCommand {
+			action += " " + options[0].Name
+		}
+	case discordgo.InteractionMessageComponent:
+		action, _, _ = parseCustomID(i.MessageComponentData().CustomID)
+	case discordgo.InteractionModalSubmit:
+		action, _, _ = parseCustomID(i.ModalSubmitData().CustomID)
+		if strings.HasPrefix(action, "format_modal_") {
+			action = "setformat"
+		}
+	}
+
+	entry := &models.AuditLogEntry{
+		GuildID:   i.GuildID,
+		ActorID:   i.Member.User.ID,
+		ActorName: i.Member.User.Username,
+		Action:    action,
+		Target:    target,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := b.Repo.AddAuditLogEntry(entry); err != nil {
+		log.Printf("[Audit] Error saving audit entry for guild %s: %v", i.GuildID, err)
+	}
+
+	settings, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		log.Printf("[Audit] Could not fetch settings for guild %s: %v", i.GuildID, err)
+		return
+	}
+	if settings.AuditChannelID == "" {
+		return
+	}
+	if _, err := s.ChannelMessageSendEmbed(settings.AuditChannelID, embed.CreateAuditEmbed(*entry)); err != nil {
+		log.Printf("[Audit] Failed to post audit entry to channel %s in guild %s: %v", settings.AuditChannelID, i.GuildID, err)
+	}
+}
+
+func (b *Bot) handleAuditLogCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	requestedPage := 1
+	var target string
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "page":
+			requestedPage = max(1, int(opt.IntValue()))
+		case "target":
+			target = extractUsernameFromURL(opt.StringValue())
+		}
+	}
+	// The target is carried in the custom IDs of the pagination buttons.
+	if len(target) > maxAuditTargetLength || strings.Contains(target, customIDSeparator) {
+		b.editInteractionResponse(s, i, fmt.Sprintf("**%s** is not a valid creator name.", embed.Truncate(target, maxAuditTargetLength)))
+		return
+	}
+
+	filter := listFilterAll
+	if target != "" {
+		filter = auditTargetFilterPrefix + target
+	}
+	b.sendPagedList(s, i, "auditlog", filter, requestedPage)
+}
+
+// auditLogItems lists the recent audit entries of a guild, optionally limited to one target.
+func (b *Bot) auditLogItems(guildID, filter string) ([]string, error) {
+	var target string
+	if strings.HasPrefix(filter, auditTargetFilterPrefix) {
+		target = strings.TrimPrefix(filter, auditTargetFilterPrefix)
+	}
+
+	entries, err := b.Repo.GetAuditLogEntries(guildID, target, auditLogLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]string, len(entries))
+	for idx, entry := range entries {
+		line := fmt.Sprintf("<t:%d:f> <@%s> used `/%s`", entry.CreatedAt, entry.ActorID, entry.Action)
+		if entry.Target != "" {
+			line += fmt.Sprintf(" on **%s**", entry.Target)
+		}
+		if entry.OldValue != "" || entry.NewValue != "" {
+			line += fmt.Sprintf("\n  %s → %s", auditValue(entry.OldValue), auditValue(entry.NewValue))
+		}
+		items[idx] = line
+	}
+	return items, nil
+}
+
+func auditValue(value string) string {
+	if value == "" {
+		return "*none*"
+	}
+	return embed.Truncate(strings.ReplaceAll(value, "\n", " "), 100)
+}
+
+func enabledText(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func channelText(channelID string) string {
+	if channelID == "" {
+		return ""
+	}
+	return fmt.Sprintf("<#%s>", channelID)
+}
+
+func roleText(roleID string) string {
+	if roleID == "" || roleID == "0" {
+		return ""
+	}
+	return getRoleName(roleID)
+}
+
+func colorText(color int) string {
+	if color == 0 {
+		return ""
+	}
+	return fmt.Sprintf("#%06X", color)
+}
+
+// describeMonitoredUser summarizes where and how a creator is announced, for audit entries.
+func describeMonitoredUser(user *models.MonitoredUser) string {
+	return fmt.Sprintf("Posts %s in %s (role: %s), live %s in %s (role: %s)",
+		enabledText(user.PostsEnabled), channelText(user.PostNotificationChannel), getRoleName(user.PostMentionRole),
+		enabledText(user.LiveEnabled), channelText(user.LiveNotificationChannel), getRoleName(user.LiveMentionRole),
+	)
+}
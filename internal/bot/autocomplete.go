@@ -0,0 +1,174 @@
+package bot
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxAutocompleteChoices is the most choices Discord accepts in an autocomplete response.
+const maxAutocompleteChoices = 25
+
+type creatorSuggestion struct {
+	Username    string
+	DisplayName string
+}
+
+// accountIndex caches Fansly accounts known to the bot, i.e. creators monitored in any
+// guild, so /add can suggest them without querying Fansly on every keystroke.
+type accountIndex struct {
+	mu       sync.RWMutex
+	accounts map[string]creatorSuggestion // Fansly ID -> account
+}
+
+func newAccountIndex() *accountIndex {
+	return &accountIndex{accounts: make(map[string]creatorSuggestion)}
+}
+
+func (idx *accountIndex) add(userID, username, displayName string) {
+	if userID == "" || username == "" {
+		return
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.accounts[userID] = creatorSuggestion{Username: username, DisplayName: displayName}
+}
+
+func (idx *accountIndex) addUsers(users []models.MonitoredUser) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, user := range users {
+		idx.accounts[user.UserID] = creatorSuggestion{Username: user.Username, DisplayName: user.DisplayName}
+	}
+}
+
+func (idx *accountIndex) search(query string) []creatorSuggestion {
+	idx.mu.RLock()
+	candidates := make([]creatorSuggestion, 0, len(idx.accounts))
+	for _, account := range idx.accounts {
+		candidates = append(candidates, account)
+	}
+	idx.mu.RUnlock()
+
+	return matchCreators(candidates, query)
+}
+
+// matchCreators returns the candidates whose username or display name contains query,
+// with prefix matches on the username first.
+func matchCreators(candidates []creatorSuggestion, query string) []creatorSuggestion {
+	query = strings.ToLower(strings.TrimPrefix(extractUsernameFromURL(strings.TrimSpace(query)), "@"))
+
+	var prefix, contains []creatorSuggestion
+	for _, c := range candidates {
+		username := strings.ToLower(c.Username)
+		switch {
+		case strings.HasPrefix(username, query):
+			prefix = append(prefix, c)
+		case strings.Contains(username, query) || strings.Contains(strings.ToLower(c.DisplayName), query):
+			contains = append(contains, c)
+		}
+	}
+
+	byName := func(list []creatorSuggestion) {
+		sort.Slice(list, func(a, b int) bool {
+			return strings.ToLower(list[a].Username) < strings.ToLower(list[b].Username)
+		})
+	}
+	byName(prefix)
+	byName(contains)
+
+	matches := append(prefix, contains...)
+	if len(matches) > maxAutocompleteChoices {
+		matches = matches[:maxAutocompleteChoices]
+	}
+	return matches
+}
+
+func (b *Bot) handleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	if data.Name == "permissions" {
+		b.autocompleteCommands(s, i)
+		return
+	}
+
+	var suggestions []creatorSuggestion
+	if focused := focusedOption(data.Options); focused != nil && (publicCommands[data.Name] || b.isBotOwner(i) || b.canRunCommand(s, i, data.Name)) {
+		query := focused.StringValue()
+		switch data.Name {
+		case "add", "follow":
+			suggestions = b.accounts.search(query)
+		case "unfollow":
+			suggestions = b.followedCreators(interactionUser(i), query)
+		default:
+			users, err := b.Repo.GetMonitoredUsersForGuild(i.GuildID)
+			if err != nil {
+				log.Printf("Error fetching monitored users for autocomplete in guild %s: %v", i.GuildID, err)
+			}
+			candidates := make([]creatorSuggestion, len(users))
+			for idx, user := range users {
+				candidates[idx] = creatorSuggestion{Username: user.Username, DisplayName: user.DisplayName}
+			}
+			suggestions = matchCreators(candidates, query)
+		}
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(suggestions))
+	for idx, suggestion := range suggestions {
+		name := suggestion.Username
+		if suggestion.DisplayName != "" && !strings.EqualFold(suggestion.DisplayName, suggestion.Username) {
+			name = embed.Truncate(suggestion.Username+" ("+suggestion.DisplayName+")", 100)
+		}
+		choices[idx] = &discordgo.ApplicationCommandOptionChoice{Name: name, Value: suggestion.Username}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to autocomplete: %v", err)
+	}
+}
+
+// autocompleteCommands suggests the commands matching the command option of /permissions.
+func (b *Bot) autocompleteCommands(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	if focused := focusedOption(i.ApplicationCommandData().Options); focused != nil && b.canRunCommand(s, i, "permissions") {
+		query := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(focused.StringValue()), "/"))
+		for _, name := range delegableCommands() {
+			if strings.Contains(name, query) && len(choices) < maxAutocompleteChoices {
+				choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: "/" + name, Value: name})
+			}
+		}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to autocomplete: %v", err)
+	}
+}
+
+// focusedOption returns the option the user is typing in, looking inside subcommands.
+func focusedOption(options []*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, opt := range options {
+		if opt.Focused {
+			return opt
+		}
+		if found := focusedOption(opt.Options); found != nil {
+			return found
+		}
+	}
+	return nil
+}
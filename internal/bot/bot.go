@@ -1,9 +1,12 @@
 package bot
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -16,9 +19,23 @@ import (
 )
 
 type Bot struct {
-	Session   *discordgo.Session
-	APIClient *api.Client
-	Repo      *database.Repository
+	Session *discordgo.Session
+	APIPool *api.Pool
+	Repo    *database.Repository
+
+	httpServer *http.Server
+	accounts   *accountIndex
+	publisher  *publisher
+	live       *liveTracker
+	follows    *followLimiter
+
+	// statusBoardMu serializes status board updates, so a board whose message is missing is posted once.
+	statusBoardMu  sync.Mutex
+	statusBoardDue chan struct{}
+
+	// ctx is cancelled by Stop so in-flight Fansly requests and background loops end promptly.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func New(aggregator *health.Aggregator) (*Bot, error) {
@@ -27,12 +44,25 @@ func New(aggregator *health.Aggregator) (*Bot, error) {
 		return nil, err
 	}
 
-	apiClient, _ := api.NewClient(config.FanslyToken, config.UserAgent, aggregator)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	apiPool, err := api.NewPool(ctx, config.FanslyTokens, config.UserAgent, aggregator)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
 
 	bot := &Bot{
-		Session:   discord,
-		APIClient: apiClient,
-		Repo:      database.NewRepository(),
+		Session:        discord,
+		APIPool:        apiPool,
+		Repo:           database.NewRepository(),
+		accounts:       newAccountIndex(),
+		publisher:      newPublisher(),
+		live:           newLiveTracker(),
+		follows:        newFollowLimiter(),
+		statusBoardDue: make(chan struct{}, 1),
+		ctx:            ctx,
+		cancel:         cancel,
 	}
 
 	bot.registerHandlers()
@@ -46,7 +76,16 @@ func (b *Bot) Start() error {
 		return err
 	}
 
+	if err := b.startHTTPServer(); err != nil {
+		return err
+	}
+
 	go b.monitorUsers()
+	go b.refreshAccountsPeriodically()
+	go b.releaseHeldNotificationsPeriodically()
+	go b.sendDigestsPeriodically()
+	go b.pruneViewerSamplesPeriodically()
+	go b.publishQueuedMessages()
 	go b.updateStatusPeriodically()
 	go b.heartbeat()
 
@@ -54,6 +93,8 @@ func (b *Bot) Start() error {
 }
 
 func (b *Bot) Stop() {
+	b.cancel()
+	b.stopHTTPServer()
 	b.Session.Close()
 }
 
@@ -111,69 +152,106 @@ func (b *Bot) monitorUsers() {
 	if numWorkers <= 0 {
 		numWorkers = 1
 	}
-	jobs := make(chan []models.MonitoredUser, 100)
+	jobs := make(chan monitorJob, 100)
 
 	for w := 1; w <= numWorkers; w++ {
 		go b.worker(w, jobs)
 	}
+	go b.updateStatusBoardsWhenDue()
 
 	log.Println("Dispatching initial monitoring cycle...")
-	b.dispatchMonitoringJobs(jobs)
+	b.runMonitoringCycle(jobs)
 
-	for range ticker.C {
-		b.dispatchMonitoringJobs(jobs)
+	for {
+		select {
+		case <-b.ctx.Done():
+			close(jobs)
+			return
+		case <-ticker.C:
+			b.runMonitoringCycle(jobs)
+		}
 	}
 }
 
-func (b *Bot) dispatchMonitoringJobs(jobs chan<- []models.MonitoredUser) {
+// runMonitoringCycle dispatches a monitoring cycle and refreshes the status boards once the
+// workers have checked every creator of it.
+func (b *Bot) runMonitoringCycle(jobs chan<- monitorJob) {
+	cycle := b.dispatchMonitoringJobs(jobs)
+	go func() {
+		cycle.Wait()
+		b.requestStatusBoardUpdate()
+	}()
+}
+
+// monitorJob is a single creator to check, together with everyone to notify about them.
+type monitorJob struct {
+	creatorID string
+	username  string
+	entries   []models.MonitoredUser      // guild entries
+	direct    []models.DirectSubscription // personal DM subscriptions
+	cycle     *sync.WaitGroup             // done once the job is checked
+}
+
+// dispatchMonitoringJobs queues a job for every monitored creator. The returned WaitGroup is
+// done once the workers have checked all of them.
+func (b *Bot) dispatchMonitoringJobs(jobs chan<- monitorJob) *sync.WaitGroup {
+	cycle := &sync.WaitGroup{}
 	users, err := b.Repo.GetMonitoredUsers()
 	if err != nil {
 		log.Printf("Error getting monitored users: %v", err)
-		return
+		return cycle
+	}
+	directSubs, directErr := b.Repo.GetActiveDirectSubscriptions()
+	if directErr != nil {
+		log.Printf("Error getting direct subscriptions: %v", directErr)
 	}
 
-	userGroups := make(map[string][]models.MonitoredUser)
+	userGroups := make(map[string]*monitorJob)
+	jobFor := func(creatorID, username string) *monitorJob {
+		job, ok := userGroups[creatorID]
+		if !ok {
+			job = &monitorJob{creatorID: creatorID, username: username, cycle: cycle}
+			userGroups[creatorID] = job
+		}
+		return job
+	}
 	for _, user := range users {
-		userGroups[user.UserID] = append(userGroups[user.UserID], user)
+		job := jobFor(user.UserID, user.Username)
+		job.entries = append(job.entries, user)
 	}
-
-	log.Printf("Dispatching %d unique users to %d workers.", len(userGroups), config.MonitorWorkerCount)
-
-	for _, userEntries := range userGroups {
-		jobs <- userEntries
+	for _, sub := range directSubs {
+		job := jobFor(sub.CreatorID, sub.Username)
+		job.direct = append(job.direct, sub)
 	}
-}
 
-func (b *Bot) worker(id int, jobs <-chan []models.MonitoredUser) {
-	avatarRefreshDuration := int64(config.AvatarRefreshIntervalHours * 60 * 60)
+	if directErr == nil {
+		b.endUntrackedStreams(userGroups)
+	}
 
-	for userEntries := range jobs {
-		primaryUser := userEntries[0]
+	log.Printf("Dispatching %d unique users to %d workers.", len(userGroups), config.MonitorWorkerCount)
 
-		if time.Now().Unix()-primaryUser.AvatarLocationUpdatedAt > avatarRefreshDuration {
-			newAvatarLocation, err := b.refreshAvatarURL(primaryUser.Username)
-			if err != nil {
-				log.Printf("[Worker %d] Error refreshing avatar URL for %s: %v", id, primaryUser.Username, err)
-			} else {
-				for _, user := range userEntries {
-					err = b.Repo.UpdateAvatarInfo(user.GuildID, user.UserID, newAvatarLocation)
-					if err != nil {
-						log.Printf("[Worker %d] Error updating avatar URL in DB for %s in guild %s: %v", id, user.Username, user.GuildID, err)
-					}
-				}
-				for i := range userEntries {
-					userEntries[i].AvatarLocation = newAvatarLocation
-				}
-			}
+	for _, job := range userGroups {
+		cycle.Add(1)
+		select {
+		case jobs <- *job:
+		case <-b.ctx.Done():
+			cycle.Done()
+			return cycle
 		}
+	}
+	return cycle
+}
 
-		b.checkUserLiveStreamOptimized(userEntries)
-		b.checkUserPostsOptimized(userEntries)
+func (b *Bot) worker(id int, jobs <-chan monitorJob) {
+	for job := range jobs {
+		b.checkUserLiveStreamOptimized(job)
+		b.checkUserPostsOptimized(job)
+		job.cycle.Done()
 	}
 }
 
-// formatNotificationMessage processes the custom message format and ensures a mention role is always included if set.
-func (b *Bot) formatNotificationMessage(guildID, userID, username, mentionRole, placeholder string) string {
+// formatNotificationMessage processes the custom message format and ensures the mention roles are always included if set.
+func (b *Bot) formatNotificationMessage(guildID, userID, username, placeholder string, mentionRoles ...string) string {
 	// 1. Fetch the custom format from the database
 	formats, err := b.Repo.GetNotificationFormats(guildID, userID)
 	if err != nil {
@@ -189,11 +267,14 @@ func (b *Bot) formatNotificationMessage(guildID, userID, username, mentionRole,
 		}
 	}
 
-	// 2. Prepare the role mention string, if a role is set
-	roleMention := ""
-	if mentionRole != "" {
-		roleMention = fmt.Sprintf("<@&%s>", mentionRole)
+	// 2. Prepare the role mention string, if any role is set
+	var mentions []string
+	for _, mentionRole := range mentionRoles {
+		if mentionRole != "" && mentionRole != "0" {
+			mentions = append(mentions, fmt.Sprintf("<@&%s>", mentionRole))
+		}
 	}
+	roleMention := strings.Join(mentions, " ")
 
 	// 3. Process the final message content
 	if customFormat != "" {
@@ -219,31 +300,51 @@ func (b *Bot) formatNotificationMessage(guildID, userID, username, mentionRole,
 	return roleMention
 }
 
-func (b *Bot) checkUserLiveStreamOptimized(userEntries []models.MonitoredUser) {
+func (b *Bot) checkUserLiveStreamOptimized(job monitorJob) {
 	liveEnabledUsers := make([]models.MonitoredUser, 0)
-	for _, user := range userEntries {
+	for _, user := range job.entries {
 		if user.LiveEnabled {
 			liveEnabledUsers = append(liveEnabledUsers, user)
 		}
 	}
+	liveDirectSubs := make([]models.DirectSubscription, 0)
+	for _, sub := range job.direct {
+		if sub.LiveEnabled {
+			liveDirectSubs = append(liveDirectSubs, sub)
+		}
+	}
 
-	if len(liveEnabledUsers) == 0 {
+	// Stream state is fetched even when nobody wants live notifications, so status boards and
+	// stream history cover every monitored creator.
+	streamInfo, err := b.APIPool.GetStreamInfo(b.ctx, job.creatorID)
+	if err != nil {
+		log.Printf("Error fetching stream info for %s: %v", job.username, err)
 		return
 	}
+	ended := b.live.update(job.creatorID, streamInfo)
+	b.recordStreamHistory(job.creatorID, streamInfo, ended)
 
-	primaryUser := liveEnabledUsers[0]
-	streamInfo, err := b.APIClient.GetStreamInfo(primaryUser.UserID)
-	if err != nil {
-		log.Printf("Error fetching stream info for %s: %v", primaryUser.Username, err)
+	b.notifyDirectLive(liveDirectSubs, streamInfo)
+	if streamInfo.Response.Stream.Status != 2 {
+		// Events are ended for every entry, including ones whose live notifications were turned off mid-stream.
+		for _, user := range job.entries {
+			if user.LiveEventID != "" {
+				b.endLiveEvent(user)
+			}
+		}
 		return
 	}
+	if len(liveEnabledUsers) == 0 {
+		return
+	}
+	primaryUser := liveEnabledUsers[0]
 
 	colorsMap, err := b.Repo.GetEmbedColorsForUser(primaryUser.UserID)
 	if err != nil {
 		log.Printf("Could not fetch embed colors for user %s: %v", primaryUser.Username, err)
 	}
 
-	if streamInfo.Response.Stream.Status == 2 && streamInfo.Response.Stream.StartedAt > primaryUser.LastStreamStart {
+	if streamInfo.Response.Stream.StartedAt > primaryUser.LastStreamStart {
 		for _, user := range liveEnabledUsers {
 			err = b.Repo.UpdateLastStreamStart(user.GuildID, user.UserID, streamInfo.Response.Stream.StartedAt)
 			if err != nil {
@@ -259,51 +360,61 @@ func (b *Bot) checkUserLiveStreamOptimized(userEntries []models.MonitoredUser) {
 			embedMsg := embed.CreateLiveStreamEmbed(user.Username, streamInfo, user.AvatarLocation, user.LiveImageURL, embedColor)
 
 			// --- FIXED THIS LINE ---
-			mentionContent := b.formatNotificationMessage(user.GuildID, user.UserID, user.Username, user.LiveMentionRole, "{liveMention}")
+			mentionContent := b.formatNotificationMessage(user.GuildID, user.UserID, user.Username, "{liveMention}", user.LiveMentionRole, user.SubscriberRoleID)
 
-			targetChannel := user.LiveNotificationChannel
-			if targetChannel == "" {
-				targetChannel = user.NotificationChannel
-			}
+			targetChannel := b.deliveryChannel(&user, deliveryLive)
 
-			_, err = b.Session.ChannelMessageSendComplex(targetChannel, &discordgo.MessageSend{
-				Content: mentionContent,
-				Embed:   embedMsg,
-			})
+			suppressMentions := mentionOnCooldown(user, time.Now())
+			_, mentioned, err := b.sendGuildNotification(user.GuildID, user.UserID, targetChannel, mentionContent, embedMsg, suppressMentions)
 			if err != nil {
 				b.logNotificationError("live stream", user, targetChannel, err)
 			} else {
 				go b.Repo.IncrementLiveCount()
+				b.recordMention(user, mentionContent, mentioned)
 			}
+
+			b.startLiveEvent(user)
 		}
 	}
 }
 
-func (b *Bot) checkUserPostsOptimized(userEntries []models.MonitoredUser) {
+func (b *Bot) checkUserPostsOptimized(job monitorJob) {
 	postEnabledUsers := make([]models.MonitoredUser, 0)
-	for _, user := range userEntries {
+	for _, user := range job.entries {
 		if user.PostsEnabled {
 			postEnabledUsers = append(postEnabledUsers, user)
 		}
 	}
+	postDirectSubs := make([]models.DirectSubscription, 0)
+	for _, sub := range job.direct {
+		if sub.PostsEnabled {
+			postDirectSubs = append(postDirectSubs, sub)
+		}
+	}
 
-	if len(postEnabledUsers) == 0 {
+	if len(postEnabledUsers) == 0 && len(postDirectSubs) == 0 {
 		return
 	}
 
-	primaryUser := postEnabledUsers[0]
-	latestPosts, err := b.APIClient.GetTimelinePost(primaryUser.UserID)
+	latestPosts, err := b.APIPool.GetTimelinePost(b.ctx, job.creatorID)
 	if err != nil {
-		log.Printf("Error fetching post info for %s: %v", primaryUser.Username, err)
+		log.Printf("Error fetching post info for %s: %v", job.username, err)
 		return
 	}
 
 	if len(latestPosts) == 0 {
 		return
 	}
+	b.recordPostHistory(job.creatorID, latestPosts)
 
 	latestPost := latestPosts[0]
 
+	b.notifyDirectPost(postDirectSubs, latestPost)
+	if len(postEnabledUsers) == 0 {
+		return
+	}
+	primaryUser := postEnabledUsers[0]
+
 	colorsMap, err := b.Repo.GetEmbedColorsForUser(primaryUser.UserID)
 	if err != nil {
 		log.Printf("Could not fetch embed colors for user %s: %v", primaryUser.Username, err)
@@ -319,6 +430,12 @@ func (b *Bot) checkUserPostsOptimized(userEntries []models.MonitoredUser) {
 
 			isFirstPostForThisServer := user.LastPostID == "" || user.LastPostID == "0"
 
+			if b.queueDigestPost(user, latestPost) {
+				continue
+			}
+
+			targetChannel := b.deliveryChannel(&user, deliveryPosts)
+
 			var embedColor int
 			if colorSetting, ok := colorsMap[user.GuildID]; ok {
 				embedColor = colorSetting.PostEmbedColor
@@ -327,23 +444,23 @@ func (b *Bot) checkUserPostsOptimized(userEntries []models.MonitoredUser) {
 			embedMsg := embed.CreatePostEmbed(user.Username, latestPost, user.AvatarLocation, nil, embedColor)
 
 			// --- FIXED THIS LINE ---
-			mentionContent := b.formatNotificationMessage(user.GuildID, user.UserID, user.Username, user.PostMentionRole, "{postMention}")
-
-			targetChannel := user.PostNotificationChannel
-			if targetChannel == "" {
-				targetChannel = user.NotificationChannel
-			}
+			mentionContent := b.formatNotificationMessage(user.GuildID, user.UserID, user.Username, "{postMention}", user.PostMentionRole, user.SubscriberRoleID)
 
 			log.Printf("Sending post notification for %s to guild %s. First post: %t", user.Username, user.GuildID, isFirstPostForThisServer)
 
-			_, err = b.Session.ChannelMessageSendComplex(targetChannel, &discordgo.MessageSend{
-				Content: mentionContent,
-				Embed:   embedMsg,
-			})
+			if b.collapseIntoBurst(user, targetChannel, latestPost.ID, embedMsg) {
+				go b.Repo.IncrementPostCount()
+				continue
+			}
+
+			suppressMentions := mentionOnCooldown(user, time.Now())
+			msg, mentioned, err := b.sendGuildNotification(user.GuildID, user.UserID, targetChannel, mentionContent, embedMsg, suppressMentions)
 			if err != nil {
 				b.logNotificationError("post", user, targetChannel, err)
 			} else {
 				go b.Repo.IncrementPostCount()
+				b.recordMention(user, mentionContent, mentioned)
+				b.startPostBurst(user, msg, latestPost.ID)
 			}
 		}
 	}
@@ -380,19 +497,6 @@ func (b *Bot) updateStatusPeriodically() {
 	}
 }
 
-func (b *Bot) refreshAvatarURL(username string) (string, error) {
-	accountInfo, err := b.APIClient.GetAccountInfo(username)
-	if err != nil {
-		return "", err
-	}
-
-	if accountInfo == nil || accountInfo.Avatar.Locations == nil || len(accountInfo.Avatar.Variants) == 0 || len(accountInfo.Avatar.Variants[0].Locations) == 0 {
-		return "", fmt.Errorf("invalid account info structure for user %s", username)
-	}
-
-	return accountInfo.Avatar.Variants[0].Locations[0].Location, nil
-}
-
 func (b *Bot) updateBotStatus() {
 	serverCount := len(b.Session.State.Guilds)
 	status := fmt.Sprintf("Watching %d servers", serverCount)
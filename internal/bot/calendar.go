@@ -0,0 +1,350 @@
+package bot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/config"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// calendarHistoryDays is how far back past streams are included in calendar feeds.
+	calendarHistoryDays = 90
+	// predictionWeeks of history are used to predict streams, and a weekly slot needs streams in
+	// at least predictionMinWeeks of them to be predicted.
+	predictionWeeks    = 8
+	predictionMinWeeks = 2
+	// predictionDays is how many days ahead predicted streams are listed. Days are counted in the
+	// guild's timezone, so a week that gains an hour to daylight saving time still ends at the
+	// same wall clock time.
+	predictionDays = 7
+
+	icsTimeFormat = "20060102T150405Z"
+)
+
+// calendarEvent is a past, ongoing or predicted stream in a calendar feed.
+type calendarEvent struct {
+	uid       string
+	username  string
+	start     time.Time
+	end       time.Time
+	live      bool
+	predicted bool
+}
+
+// calendarFeedURL returns the feed URL of a guild, or of one creator if username is set.
+func calendarFeedURL(token, username string) string {
+	if username == "" {
+		return fmt.Sprintf("%s%s/%s.ics", config.PublicURL, config.CalendarPath, token)
+	}
+	return fmt.Sprintf("%s%s/%s/%s.ics", config.PublicURL, config.CalendarPath, token, url.PathEscape(strings.ToLower(username)))
+}
+
+func newCalendarToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// calendarHandler serves <token>.ics with the streams of every creator a guild monitors and
+// <token>/<username>.ics with the streams of one of them.
+func (b *Bot) calendarHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		path, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, config.CalendarPath+"/"), ".ics")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		token, username, _ := strings.Cut(path, "/")
+
+		settings, err := b.Repo.GetGuildSettingsByCalendarToken(token)
+		if err != nil {
+			log.Printf("[Calendar] Error looking up calendar token: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if settings == nil || token == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		users, err := b.Repo.GetMonitoredUsersForGuild(settings.GuildID)
+		if err != nil {
+			log.Printf("[Calendar] Error fetching monitored users for guild %s: %v", settings.GuildID, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if username != "" {
+			users = slices.DeleteFunc(users, func(user models.MonitoredUser) bool { return !strings.EqualFold(user.Username, username) })
+			if len(users) == 0 {
+				http.NotFound(w, r)
+				return
+			}
+		}
+
+		events, err := b.calendarEvents(users, guildLocation(settings), time.Now())
+		if err != nil {
+			log.Printf("[Calendar] Error fetching stream history for guild %s: %v", settings.GuildID, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		name := "Fansly streams"
+		if guild, err := b.Session.State.Guild(settings.GuildID); err == nil {
+			name = guild.Name + " · Fansly streams"
+		}
+		if username != "" {
+			name = users[0].Username + " · Fansly streams"
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Cache-Control", "private, max-age=300")
+		w.Write([]byte(renderCalendar(name, events, time.Now())))
+	})
+}
+
+// calendarEvents returns the recorded streams of the last calendarHistoryDays and the predicted
+// streams of the next predictionDays for each creator.
+func (b *Bot) calendarEvents(users []models.MonitoredUser, loc *time.Location, now time.Time) ([]calendarEvent, error) {
+	since := now.AddDate(0, 0, -calendarHistoryDays).Unix()
+
+	var events []calendarEvent
+	for _, user := range users {
+		sessions, err := b.Repo.GetStreamSessions(user.UserID, since)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, session := range sessions {
+			event := calendarEvent{
+				uid:      fmt.Sprintf("stream-%s-%d@notifansly", user.UserID, session.StartedAt),
+				username: user.Username,
+				start:    time.Unix(session.StartedAt, 0),
+				end:      time.Unix(session.EndedAt, 0),
+			}
+			if session.EndedAt == 0 {
+				event.end, event.live = now, true
+			}
+			events = append(events, event)
+		}
+
+		for _, predicted := range predictStreams(sessions, loc, now) {
+			predicted.uid = fmt.Sprintf("predicted-%s-%d@notifansly", user.UserID, predicted.start.Unix())
+			predicted.username = user.Username
+			events = append(events, predicted)
+		}
+	}
+	sort.Slice(events, func(a, c int) bool { return events[a].start.Before(events[c].start) })
+	return events, nil
+}
+
+// predictStreams finds weekly slots (weekday and hour in the guild's timezone) in which the creator
+// went live in at least predictionMinWeeks of the last predictionWeeks weeks, and returns the next
+// occurrence of each slot at the slot's average start time and stream length.
+func predictStreams(sessions []models.StreamSession, loc *time.Location, now time.Time) []calendarEvent {
+	type slot struct {
+		weekday time.Weekday
+		hour    int
+	}
+	type slotStats struct {
+		weeks    map[int]bool
+		minutes  int
+		starts   int
+		duration time.Duration
+		ended    int
+	}
+
+	slots := make(map[slot]*slotStats)
+	cutoff := now.AddDate(0, 0, -7*predictionWeeks).Unix()
+	for _, session := range sessions {
+		if session.StartedAt < cutoff {
+			continue
+		}
+		start := time.Unix(session.StartedAt, 0).In(loc)
+		key := slot{start.Weekday(), start.Hour()}
+		stats := slots[key]
+		if stats == nil {
+			stats = &slotStats{weeks: make(map[int]bool)}
+			slots[key] = stats
+		}
+		year, week := start.ISOWeek()
+		stats.weeks[year*100+week] = true
+		stats.minutes += start.Minute()
+		stats.starts++
+		if session.EndedAt != 0 {
+			stats.duration += time.Duration(session.EndedAt-session.StartedAt) * time.Second
+			stats.ended++
+		}
+	}
+
+	var predicted []calendarEvent
+	today := now.In(loc)
+	horizon := today.AddDate(0, 0, predictionDays)
+	for key, stats := range slots {
+		if len(stats.weeks) < predictionMinWeeks || stats.ended == 0 {
+			continue
+		}
+		for day := 0; day <= predictionDays; day++ {
+			date := today.AddDate(0, 0, day)
+			if date.Weekday() != key.weekday {
+				continue
+			}
+			start := time.Date(date.Year(), date.Month(), date.Day(), key.hour, stats.minutes/stats.starts, 0, 0, loc)
+			if start.After(now) && start.Before(horizon) {
+				predicted = append(predicted, calendarEvent{
+					start:     start,
+					end:       start.Add(stats.duration / time.Duration(stats.ended)),
+					predicted: true,
+				})
+				break
+			}
+		}
+	}
+	return predicted
+}
+
+// renderCalendar writes events as an iCalendar (RFC 5545) document.
+func renderCalendar(name string, events []calendarEvent, now time.Time) string {
+	var sb strings.Builder
+	line := func(text string) {
+		// Lines longer than 75 octets are folded onto continuation lines starting with a space.
+		for len(text) > 75 {
+			cut := 75
+			for cut > 0 && text[cut]&0xc0 == 0x80 {
+				cut--
+			}
+			sb.WriteString(text[:cut] + "\r\n")
+			text = " " + text[cut:]
+		}
+		sb.WriteString(text + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//NotiFansly//Stream Calendar//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + icsEscape(name))
+	line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	line("X-PUBLISHED-TTL:PT1H")
+
+	stamp := now.UTC().Format(icsTimeFormat)
+	for _, event := range events {
+		summary := fmt.Sprintf("%s live on Fansly", event.username)
+		description := "Recorded by NotiFansly."
+		status := "CONFIRMED"
+		switch {
+		case event.predicted:
+			summary = fmt.Sprintf("%s usually live (predicted)", event.username)
+			description = "Predicted from the creator's recent streams; not announced by the creator."
+			status = "TENTATIVE"
+		case event.live:
+			summary = fmt.Sprintf("🔴 %s live now on Fansly", event.username)
+			description = "This stream is still live."
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:" + event.uid)
+		line("DTSTAMP:" + stamp)
+		line("DTSTART:" + event.start.UTC().Format(icsTimeFormat))
+		line("DTEND:" + event.end.UTC().Format(icsTimeFormat))
+		line("SUMMARY:" + icsEscape(summary))
+		line("DESCRIPTION:" + icsEscape(description))
+		line("URL:https://fansly.com/live/" + event.username)
+		line("STATUS:" + status)
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return sb.String()
+}
+
+func icsEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
+
+func (b *Bot) handleCalendarCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !calendarFeedsEnabled() {
+		b.respondToInteraction(s, i, "Calendar feeds are not enabled on this bot.", true)
+		return
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	var username string
+	var regenerate bool
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "username":
+			username = opt.StringValue()
+		case "regenerate":
+			regenerate = opt.BoolValue()
+		}
+	}
+
+	if username != "" {
+		user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+		if err != nil || user == nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Creator **%s** is not being monitored in this server.", username))
+			return
+		}
+		username = user.Username
+	}
+
+	settings, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching settings: %v", err))
+		return
+	}
+
+	token := settings.CalendarToken
+	if token == "" || regenerate {
+		token, err = newCalendarToken()
+		if err == nil {
+			err = b.Repo.UpdateGuildSettings(i.GuildID, map[string]any{"calendar_token": token})
+		}
+		if err != nil {
+			log.Printf("Error creating calendar token for guild %s: %v", i.GuildID, err)
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error creating the calendar link: %v", err))
+			return
+		}
+	}
+
+	msg := fmt.Sprintf("Subscribe to this URL in your calendar app:\n%s\n\nThe feed lists streams of the last %d days and predicted streams for the next week. Anyone with the link can read it; use `regenerate` to revoke all existing links.",
+		calendarFeedURL(token, username), calendarHistoryDays)
+	if regenerate && settings.CalendarToken != "" {
+		msg = "Previous calendar links no longer work.\n" + msg
+	}
+	b.editInteractionResponse(s, i, msg)
+
+	if regenerate && settings.CalendarToken != "" {
+		b.recordAudit(s, i, "", "calendar links", "calendar links regenerated")
+	}
+}
@@ -0,0 +1,178 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+)
+
+func TestPredictStreams(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, loc)
+	}
+	stream := func(start time.Time, length time.Duration) models.StreamSession {
+		return models.StreamSession{StartedAt: start.Unix(), EndedAt: start.Add(length).Unix()}
+	}
+
+	tests := []struct {
+		name     string
+		sessions []models.StreamSession
+		now      time.Time
+		want     []time.Time
+	}{
+		{
+			name: "weekly slot later this week",
+			sessions: []models.StreamSession{
+				stream(at(2025, 5, 20, 20, 0), 2*time.Hour),
+				stream(at(2025, 5, 27, 20, 30), 2*time.Hour),
+			},
+			now:  at(2025, 6, 2, 12, 0),
+			want: []time.Time{at(2025, 6, 3, 20, 15)},
+		},
+		{
+			name: "slot seen in a single week",
+			sessions: []models.StreamSession{
+				stream(at(2025, 5, 27, 20, 0), 2*time.Hour),
+				stream(at(2025, 5, 27, 20, 45), time.Hour),
+			},
+			now: at(2025, 6, 2, 12, 0),
+		},
+		{
+			name: "streams older than the prediction window",
+			sessions: []models.StreamSession{
+				stream(at(2025, 1, 7, 20, 0), 2*time.Hour),
+				stream(at(2025, 1, 14, 20, 0), 2*time.Hour),
+			},
+			now: at(2025, 6, 2, 12, 0),
+		},
+		{
+			name: "only streams that are still live",
+			sessions: []models.StreamSession{
+				{StartedAt: at(2025, 5, 20, 20, 0).Unix()},
+				{StartedAt: at(2025, 5, 27, 20, 0).Unix()},
+			},
+			now: at(2025, 6, 2, 12, 0),
+		},
+		{
+			name: "Sunday night slot across ISO week boundary",
+			sessions: []models.StreamSession{
+				stream(at(2025, 5, 18, 23, 30), 2*time.Hour),
+				stream(at(2025, 5, 25, 23, 30), 2*time.Hour),
+			},
+			now:  at(2025, 5, 31, 12, 0),
+			want: []time.Time{at(2025, 6, 1, 23, 30)},
+		},
+		{
+			name: "today's slot has passed",
+			sessions: []models.StreamSession{
+				stream(at(2025, 5, 25, 23, 30), time.Hour),
+				stream(at(2025, 6, 1, 23, 30), time.Hour),
+			},
+			now:  at(2025, 6, 8, 23, 45),
+			want: []time.Time{at(2025, 6, 15, 23, 30)},
+		},
+		{
+			name: "next occurrence after the clocks fall back",
+			sessions: []models.StreamSession{
+				stream(at(2025, 10, 18, 20, 0), 2*time.Hour),
+				stream(at(2025, 10, 25, 20, 0), 2*time.Hour),
+			},
+			now:  at(2025, 11, 1, 20, 30),
+			want: []time.Time{at(2025, 11, 8, 20, 0)},
+		},
+		{
+			name: "history recorded before the clocks spring forward",
+			sessions: []models.StreamSession{
+				stream(at(2025, 2, 25, 20, 0), 2*time.Hour),
+				stream(at(2025, 3, 4, 20, 0), 2*time.Hour),
+			},
+			now:  at(2025, 3, 10, 12, 0),
+			want: []time.Time{at(2025, 3, 11, 20, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := predictStreams(tt.sessions, loc, tt.now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d predictions, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for idx, event := range got {
+				if !event.start.Equal(tt.want[idx]) {
+					t.Errorf("prediction %d starts at %s, want %s", idx, event.start, tt.want[idx])
+				}
+				if !event.predicted {
+					t.Errorf("prediction %d is not marked as predicted", idx)
+				}
+				if !event.end.After(event.start) {
+					t.Errorf("prediction %d ends at %s, before it starts", idx, event.end)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderCalendarFoldsLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar string
+	}{
+		{name: "ASCII", calendar: strings.Repeat("streams ", 30)},
+		{name: "two byte runes", calendar: strings.Repeat("é", 100)},
+		{name: "three byte runes", calendar: strings.Repeat("日本語", 40)},
+		{name: "emoji", calendar: "x" + strings.Repeat("🔴", 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := renderCalendar(tt.calendar, nil, time.Unix(0, 0))
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("calendar does not end with CRLF: %q", out)
+			}
+
+			var unfolded strings.Builder
+			for idx, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+				if len(line) > 75 {
+					t.Errorf("line %d is %d octets long: %q", idx, len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", idx, line)
+				}
+				if continuation, ok := strings.CutPrefix(line, " "); ok {
+					unfolded.WriteString(continuation)
+				} else {
+					unfolded.WriteString("\n" + line)
+				}
+			}
+			if !strings.Contains(unfolded.String(), "\nX-WR-CALNAME:"+tt.calendar+"\n") {
+				t.Errorf("unfolded calendar does not contain the calendar name:\n%s", unfolded.String())
+			}
+		})
+	}
+}
+
+func TestICSEscape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "plain text", want: "plain text"},
+		{in: "a, b; c", want: `a\, b\; c`},
+		{in: `back\slash`, want: `back\\slash`},
+		{in: "two\nlines", want: `two\nlines`},
+		{in: `\;`, want: `\\\;`},
+	}
+
+	for _, tt := range tests {
+		if got := icsEscape(tt.in); got != tt.want {
+			t.Errorf("icsEscape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -3,25 +3,68 @@ package bot
 import (
 	"github.com/bwmarrin/discordgo"
 	"log"
+	"sync"
+	"time"
 )
 
 func (b *Bot) registerCommands() {
+	_, err := b.Session.ApplicationCommandBulkOverwrite(b.Session.State.User.ID, "", applicationCommands())
+	if err != nil {
+		log.Printf("Error registering commands: %v", err)
+	}
+}
+
+// applicationCommands returns every slash command of the bot.
+func applicationCommands() []*discordgo.ApplicationCommand {
 	commands := []*discordgo.ApplicationCommand{
 		{
 			Name:        "add",
 			Description: "Add a Fansly model to monitor",
 			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
+				},
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Notification channel",
+					Required:     true,
+					ChannelTypes: deliveryChannelTypes,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionRole,
+					Name:        "mention_role",
+					Description: "Role to mention (optional)",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "addmany",
+			Description: "Add several Fansly models at once",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Notification channel",
+					Required:     true,
+					ChannelTypes: deliveryChannelTypes,
+				},
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
+					Name:        "usernames",
+					Description: "Usernames or Fansly links, separated by spaces or commas",
+					Required:    false,
 				},
 				{
-					Type:        discordgo.ApplicationCommandOptionChannel,
-					Name:        "channel",
-					Description: "Notification channel",
-					Required:    true,
+					Type:        discordgo.ApplicationCommandOptionAttachment,
+					Name:        "file",
+					Description: "A text file with one username or link per line",
+					Required:    false,
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionRole,
@@ -36,10 +79,11 @@ func (b *Bot) registerCommands() {
 			Description: "Remove a Fansly model from monitoring",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
 				},
 			},
 		},
@@ -60,10 +104,11 @@ func (b *Bot) registerCommands() {
 			Description: "Set a custom live image for a model",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "The username of the model",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "The username of the model",
+					Required:     true,
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionAttachment,
@@ -78,10 +123,11 @@ func (b *Bot) registerCommands() {
 			Description: "Toggle notifications for a model",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
@@ -97,6 +143,10 @@ func (b *Bot) registerCommands() {
 							Name:  "Live",
 							Value: "live",
 						},
+						{
+							Name:  "Profile changes",
+							Value: "profile",
+						},
 					},
 				},
 				{
@@ -112,10 +162,11 @@ func (b *Bot) registerCommands() {
 			Description: "Set notification channel for posts or live notifications",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
@@ -134,10 +185,11 @@ func (b *Bot) registerCommands() {
 					},
 				},
 				{
-					Type:        discordgo.ApplicationCommandOptionChannel,
-					Name:        "channel",
-					Description: "The notification channel",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "The notification channel",
+					Required:     true,
+					ChannelTypes: deliveryChannelTypes,
 				},
 			},
 		},
@@ -146,10 +198,11 @@ func (b *Bot) registerCommands() {
 			Description: "Set role to mention for post notifications",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionRole,
@@ -164,10 +217,11 @@ func (b *Bot) registerCommands() {
 			Description: "Set role to mention for live notifications",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionRole,
@@ -182,10 +236,11 @@ func (b *Bot) registerCommands() {
 			Description: "Set a custom embed color for a creator's notifications.",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "The username of the creator to update.",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "The username of the creator to update.",
+					Required:     true,
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
@@ -216,10 +271,11 @@ func (b *Bot) registerCommands() {
 			Description: "Set a custom notification message format for a creator.",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "The username of the creator to update.",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "The username of the creator to update.",
+					Required:     true,
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
@@ -239,6 +295,412 @@ func (b *Bot) registerCommands() {
 				},
 			},
 		},
+		{
+			Name:        "settings",
+			Description: "Open an interactive settings panel for a creator",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Description:  "Fansly username",
+					Required:     true,
+					Autocomplete: true,
+				},
+			},
+		},
+		{
+			Name:        "roles",
+			Description: "Let members pick which creators they get mentioned for.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "enable",
+					Description: "Create a notification role for a creator.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "username",
+							Description:  "Fansly username",
+							Required:     true,
+							Autocomplete: true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "disable",
+					Description: "Delete the notification role of a creator.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "username",
+							Description:  "Fansly username",
+							Required:     true,
+							Autocomplete: true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "picker",
+					Description: "Post a message members can use to pick their notification roles.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionChannel,
+							Name:         "channel",
+							Description:  "The channel to post the role picker in",
+							Required:     true,
+							ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "config",
+			Description: "Configure server-wide notification settings.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "renames",
+					Description: "Announce when a monitored creator changes their username.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "enabled",
+							Description: "Post a message in the notification channel when a creator renames",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "auditchannel",
+					Description: "Post every configuration change to a channel.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionChannel,
+							Name:         "channel",
+							Description:  "The audit channel (leave empty to stop posting changes)",
+							Required:     false,
+							ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "timezone",
+					Description: "Set the timezone used for quiet hours.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "zone",
+							Description: "IANA timezone, e.g. Europe/Berlin or America/New_York",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "quiethours",
+					Description: "Suppress mentions or hold notifications during a daily window.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "start",
+							Description: "Start time as HH:MM in the server's timezone (leave empty to disable)",
+							Required:    false,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "end",
+							Description: "End time as HH:MM in the server's timezone",
+							Required:    false,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "mode",
+							Description: "What happens during quiet hours (default: mute)",
+							Required:    false,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "Post without pinging anyone", Value: quietModeMute},
+								{Name: "Hold and post when quiet hours end", Value: quietModeHold},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "autopublish",
+					Description: "Publish notifications sent to announcement channels to following servers.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "enabled",
+							Description: "Automatically publish notifications in announcement channels",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "liveevents",
+					Description: "Show live creators as events in the server's Events tab.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "enabled",
+							Description: "Create an event when a creator goes live and end it when the stream ends",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "digest",
+					Description: "Collect post notifications into a scheduled summary.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "schedule",
+							Description: "How often to send the digest",
+							Required:    true,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "Off", Value: "off"},
+								{Name: "Daily", Value: digestDaily},
+								{Name: "Weekly", Value: digestWeekly},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "time",
+							Description: "Time to send the digest as HH:MM in the server's timezone (default: 09:00)",
+							Required:    false,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "day",
+							Description: "Day of weekly digests (default: Monday)",
+							Required:    false,
+							Choices:     weekdayChoices(),
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "all_creators",
+							Description: "Send every creator's posts to the digest (default: only creators set with /digest)",
+							Required:    false,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "auditlog",
+			Description: "Show recent configuration changes in this server.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "target",
+					Description:  "Only show changes to this creator",
+					Autocomplete: true,
+					Required:     false,
+					MaxLength:    maxAuditTargetLength,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "page",
+					Description: "Page number to display",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "digest",
+			Description: "Choose whether a model's posts are sent immediately or in the digest",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "delivery",
+					Description: "How post notifications are delivered",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Server default", Value: "default"},
+						{Name: "Immediately", Value: postDeliveryImmediate},
+						{Name: "In the digest", Value: postDeliveryDigest},
+					},
+				},
+			},
+		},
+		{
+			Name:        "cooldown",
+			Description: "Limit how often a model's notifications ping and collapse bursts of posts",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "mention_minutes",
+					Description: "Ping at most once in this many minutes (0 to ping every time)",
+					Required:    false,
+					MinValue:    &zeroMinutes,
+					MaxValue:    maxCooldownMinutes,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "burst_minutes",
+					Description: "Add posts made within this many minutes to the previous notification (0 to disable)",
+					Required:    false,
+					MinValue:    &zeroMinutes,
+					MaxValue:    maxCooldownMinutes,
+				},
+			},
+		},
+		{
+			Name:        "statusboard",
+			Description: "Keep a live-updating list of live models and a live counter channel",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Channel for the status board (leave both empty to disable)",
+					Required:     false,
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionChannel,
+					Name:        "counter_channel",
+					Description: "Channel renamed to show how many models are live",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "creatorstats",
+			Description: "Show a model's posting and streaming statistics",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "period",
+					Description: "Period to summarize (default: last 30 days)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Last 7 days", Value: "7d"},
+						{Name: "Last 30 days", Value: "30d"},
+						{Name: "Last 90 days", Value: "90d"},
+						{Name: "All time", Value: "all"},
+					},
+				},
+			},
+		},
+		{
+			Name:        "activity",
+			Description: "Show charts of a model's posts and live streams",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "period",
+					Description: "Period to chart (default: last 30 days)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Last 7 days", Value: "7d"},
+						{Name: "Last 30 days", Value: "30d"},
+						{Name: "Last 90 days", Value: "90d"},
+					},
+				},
+			},
+		},
+		{
+			Name:        "calendar",
+			Description: "Get a calendar feed (.ics) of models' past and predicted live streams",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Only include this model (default: every model in this server)",
+					Required:     false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "regenerate",
+					Description: "Create a new link and revoke all existing ones",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:             "follow",
+			Description:      "Get DM notifications for a Fansly model.",
+			Contexts:         &personalCommandContexts,
+			IntegrationTypes: &personalCommandIntegrations,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "type",
+					Description: "Which notifications to receive (default: all)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Posts and live streams", Value: "all"},
+						{Name: "Posts only", Value: "posts"},
+						{Name: "Live streams only", Value: "live"},
+					},
+				},
+			},
+		},
+		{
+			Name:             "unfollow",
+			Description:      "Stop DM notifications for a Fansly model.",
+			Contexts:         &personalCommandContexts,
+			IntegrationTypes: &personalCommandIntegrations,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "username",
+					Autocomplete: true,
+					Description:  "Fansly username",
+					Required:     true,
+				},
+			},
+		},
+		{
+			Name:             "following",
+			Description:      "List the models you get DM notifications for.",
+			Contexts:         &personalCommandContexts,
+			IntegrationTypes: &personalCommandIntegrations,
+		},
 		// --- NEW BOT OWNER COMMANDS ---
 		{
 			Name:        "servers",
@@ -290,8 +752,139 @@ func (b *Bot) registerCommands() {
 		},
 	}
 
-	_, err := b.Session.ApplicationCommandBulkOverwrite(b.Session.State.User.ID, "", commands)
-	if err != nil {
-		log.Printf("Error registering commands: %v", err)
+	return append(commands, permissionsCommand())
+}
+
+// zeroMinutes is the lower bound of minute options; MinValue needs an addressable value.
+var zeroMinutes = 0.0
+
+func weekdayChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 7)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		choices[day] = &discordgo.ApplicationCommandOptionChoice{Name: day.String(), Value: int(day)}
+	}
+	return choices
+}
+
+// ownerOnlyCommands are never delegated to guild roles.
+var ownerOnlyCommands = map[string]bool{
+	"servers":  true,
+	"leave":    true,
+	"setlimit": true,
+}
+
+// personalCommandContexts and personalCommandIntegrations let the DM subscription commands
+// be used anywhere, including in DMs with the bot and when the app is installed to a user.
+var (
+	personalCommandContexts = []discordgo.InteractionContextType{
+		discordgo.InteractionContextGuild,
+		discordgo.InteractionContextBotDM,
+		discordgo.InteractionContextPrivateChannel,
+	}
+	personalCommandIntegrations = []discordgo.ApplicationIntegrationType{
+		discordgo.ApplicationIntegrationGuildInstall,
+		discordgo.ApplicationIntegrationUserInstall,
+	}
+)
+
+// publicCommands manage the caller's own DM subscriptions and are open to everyone.
+var publicCommands = map[string]bool{
+	"follow":    true,
+	"unfollow":  true,
+	"following": true,
+}
+
+// delegableCommands lists the commands that can be delegated to roles with /permissions.
+var delegableCommands = sync.OnceValue(func() []string {
+	var names []string
+	for _, cmd := range applicationCommands() {
+		if ownerOnlyCommands[cmd.Name] || publicCommands[cmd.Name] || cmd.Name == "permissions" {
+			continue
+		}
+		names = append(names, cmd.Name)
+	}
+	return names
+})
+
+// permissionsCommand builds /permissions. Its command option is autocompleted from
+// delegableCommands, so new commands show up automatically and are not bound by
+// Discord's limit of 25 static choices.
+func permissionsCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "permissions",
+		Description: "Manage which roles can use the bot's commands.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "view",
+				Description: "Show the manager roles and command overrides of this server.",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "addmanager",
+				Description: "Allow a role to use all management commands.",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "role",
+						Description: "The manager role",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "removemanager",
+				Description: "Remove a manager role.",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "role",
+						Description: "The manager role to remove",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "allow",
+				Description: "Allow a role to use one command. Overrides the manager roles for that command.",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "command",
+						Description:  "The command",
+						Required:     true,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "role",
+						Description: "The role allowed to use it (@everyone for all members)",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Remove command overrides so the manager roles apply again.",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "command",
+						Description:  "The command",
+						Required:     true,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "role",
+						Description: "Only remove the override for this role",
+						Required:    false,
+					},
+				},
+			},
+		},
 	}
 }
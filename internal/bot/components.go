@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Custom IDs of components and modals handled by the router have the form
+// "namespace:action:arg1:arg2...". The namespace selects the feature, the action what to do.
+const customIDSeparator = ":"
+
+func makeCustomID(namespace, action string, args ...string) string {
+	return strings.Join(append([]string{namespace, action}, args...), customIDSeparator)
+}
+
+func parseCustomID(customID string) (namespace, action string, args []string) {
+	parts := strings.Split(customID, customIDSeparator)
+	namespace = parts[0]
+	if len(parts) > 1 {
+		action = parts[1]
+	}
+	if len(parts) > 2 {
+		args = parts[2:]
+	}
+	return namespace, action, args
+}
+
+func (b *Bot) handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	namespace, action, args := parseCustomID(i.MessageComponentData().CustomID)
+
+	switch namespace {
+	case settingsNamespace:
+		if !b.authorizeComponent(s, i, "settings") {
+			return
+		}
+		b.handleSettingsComponent(s, i, action, args)
+	case pageNamespace:
+		b.handlePageComponent(s, i, action, args)
+	case rolesNamespace:
+		b.handleRolesComponent(s, i, action, args)
+	case addNamespace:
+		if !b.authorizeComponent(s, i, "add") {
+			return
+		}
+		b.handleAddComponent(s, i, action, args)
+	default:
+		log.Printf("Received component with unknown custom ID: %s", i.MessageComponentData().CustomID)
+	}
+}
+
+func (b *Bot) handleModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	customID := i.ModalSubmitData().CustomID
+	if strings.HasPrefix(customID, "format_modal_") {
+		b.handleFormatModalSubmit(s, i)
+		return
+	}
+
+	namespace, action, args := parseCustomID(customID)
+	switch namespace {
+	case settingsNamespace:
+		if !b.authorizeComponent(s, i, "settings") {
+			return
+		}
+		b.handleSettingsModal(s, i, action, args)
+	case pageNamespace:
+		b.handlePageModal(s, i, action, args)
+	default:
+		log.Printf("Received modal with unknown custom ID: %s", customID)
+	}
+}
+
+// authorizeComponent applies the permission rules of command to a component or modal
+// interaction, telling the user if they are not allowed to use it.
+func (b *Bot) authorizeComponent(s *discordgo.Session, i *discordgo.InteractionCreate, command string) bool {
+	if b.isBotOwner(i) || b.canRunCommand(s, i, command) {
+		return true
+	}
+	b.respondToInteraction(s, i, "You do not have permission to use this.", true)
+	return false
+}
+
+// modalValues returns the values of all text inputs of a submitted modal by custom ID.
+func modalValues(data discordgo.ModalSubmitInteractionData) map[string]string {
+	values := make(map[string]string)
+	for _, component := range data.Components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, inner := range row.Components {
+			if input, ok := inner.(*discordgo.TextInput); ok {
+				values[input.CustomID] = input.Value
+			}
+		}
+	}
+	return values
+}
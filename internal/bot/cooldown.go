@@ -0,0 +1,136 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxCooldownMinutes caps the mention cooldown and burst window at one day.
+const maxCooldownMinutes = 24 * 60
+
+// mentionOnCooldown reports whether a creator pinged recently enough that this notification should stay silent.
+func mentionOnCooldown(user models.MonitoredUser, now time.Time) bool {
+	return user.MentionCooldownMinutes > 0 && now.Unix()-user.LastMentionAt < int64(user.MentionCooldownMinutes)*60
+}
+
+// recordMention starts a creator's mention cooldown after a notification pinged.
+func (b *Bot) recordMention(user models.MonitoredUser, content string, mentioned bool) {
+	if user.MentionCooldownMinutes <= 0 || !mentioned || content == "" {
+		return
+	}
+	if err := b.Repo.UpdateLastMentionAt(user.GuildID, user.UserID, time.Now().Unix()); err != nil {
+		log.Printf("Error updating last mention time for %s in guild %s: %v", user.Username, user.GuildID, err)
+	}
+}
+
+// collapseIntoBurst edits the creator's recent post notification to include postID instead of
+// sending a new message, and reports whether it did.
+func (b *Bot) collapseIntoBurst(user models.MonitoredUser, channelID, postID string, embedMsg *discordgo.MessageEmbed) bool {
+	if user.BurstWindowMinutes <= 0 {
+		return false
+	}
+	burst, err := b.Repo.GetPostBurst(user.GuildID, user.UserID)
+	if err != nil {
+		log.Printf("Error fetching post burst for %s in guild %s: %v", user.Username, user.GuildID, err)
+		return false
+	}
+	if burst == nil || burst.ChannelID != channelID || time.Now().Unix()-burst.StartedAt > int64(user.BurstWindowMinutes)*60 {
+		return false
+	}
+
+	postIDs := append([]string{postID}, strings.Split(burst.PostIDs, ",")...)
+	embed.CollapsePostBurst(embedMsg, user.Username, postIDs)
+	_, err = b.Session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:      burst.MessageID,
+		Channel: burst.ChannelID,
+		Embeds:  &[]*discordgo.MessageEmbed{embedMsg},
+	})
+	if err != nil {
+		// The message was probably deleted; a new notification starts a new burst.
+		log.Printf("Could not collapse post of %s into message %s in guild %s: %v", user.Username, burst.MessageID, user.GuildID, err)
+		return false
+	}
+
+	burst.PostIDs = strings.Join(postIDs, ",")
+	if err := b.Repo.SavePostBurst(burst); err != nil {
+		log.Printf("Error saving post burst for %s in guild %s: %v", user.Username, user.GuildID, err)
+	}
+	return true
+}
+
+// startPostBurst remembers a freshly sent post notification so later posts can be collapsed into it.
+func (b *Bot) startPostBurst(user models.MonitoredUser, msg *discordgo.Message, postID string) {
+	if user.BurstWindowMinutes <= 0 || msg == nil {
+		return
+	}
+	err := b.Repo.SavePostBurst(&models.PostBurst{
+		GuildID:   user.GuildID,
+		UserID:    user.UserID,
+		ChannelID: msg.ChannelID,
+		MessageID: msg.ID,
+		PostIDs:   postID,
+		StartedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		log.Printf("Error saving post burst for %s in guild %s: %v", user.Username, user.GuildID, err)
+	}
+}
+
+func (b *Bot) handleCooldownCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	username := options[0].StringValue()
+
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || user == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Creator **%s** is not being monitored in this server.", username))
+		return
+	}
+
+	mentionCooldown, burstWindow := user.MentionCooldownMinutes, user.BurstWindowMinutes
+	for _, opt := range options[1:] {
+		switch opt.Name {
+		case "mention_minutes":
+			mentionCooldown = int(opt.IntValue())
+		case "burst_minutes":
+			burstWindow = int(opt.IntValue())
+		}
+	}
+	if len(options) == 1 {
+		b.editInteractionResponse(s, i, fmt.Sprintf("**%s**: %s.", user.Username, describeCooldowns(mentionCooldown, burstWindow)))
+		return
+	}
+
+	if err := b.Repo.SetNotificationCooldownsByUsername(i.GuildID, username, mentionCooldown, burstWindow); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating cooldowns: %v", err))
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("✅ **%s**: %s.", user.Username, describeCooldowns(mentionCooldown, burstWindow)))
+	b.recordAudit(s, i, user.Username, describeCooldowns(user.MentionCooldownMinutes, user.BurstWindowMinutes), describeCooldowns(mentionCooldown, burstWindow))
+}
+
+func describeCooldowns(mentionCooldown, burstWindow int) string {
+	mention := "pings on every notification"
+	if mentionCooldown > 0 {
+		mention = fmt.Sprintf("pings at most once every %d minute(s)", mentionCooldown)
+	}
+	burst := "every post is sent separately"
+	if burstWindow > 0 {
+		burst = fmt.Sprintf("posts within %d minute(s) of a notification are added to it", burstWindow)
+	}
+	return mention + ", " + burst
+}
@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+)
+
+func TestMentionOnCooldown(t *testing.T) {
+	now := time.Unix(1_750_000_000, 0)
+
+	tests := []struct {
+		name          string
+		cooldown      int
+		lastMentionAt int64
+		want          bool
+	}{
+		{name: "no cooldown", cooldown: 0, lastMentionAt: now.Unix(), want: false},
+		{name: "never mentioned", cooldown: 30, lastMentionAt: 0, want: false},
+		{name: "mentioned within the cooldown", cooldown: 30, lastMentionAt: now.Add(-10 * time.Minute).Unix(), want: true},
+		{name: "mentioned just now", cooldown: 30, lastMentionAt: now.Unix(), want: true},
+		{name: "cooldown just expired", cooldown: 30, lastMentionAt: now.Add(-30 * time.Minute).Unix(), want: false},
+		{name: "mentioned long ago", cooldown: 30, lastMentionAt: now.Add(-2 * time.Hour).Unix(), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := models.MonitoredUser{MentionCooldownMinutes: tt.cooldown, LastMentionAt: tt.lastMentionAt}
+			if got := mentionOnCooldown(user, now); got != tt.want {
+				t.Errorf("mentionOnCooldown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
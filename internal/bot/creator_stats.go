@@ -0,0 +1,219 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/api"
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+// statsPeriods maps the /creatorstats period choices to their length in days; 0 means all time.
+var statsPeriods = map[string]int{"7d": 7, "30d": 30, "90d": 90, "all": 0}
+
+const (
+	// typicalHourCount is how many of the busiest posting hours /creatorstats shows.
+	typicalHourCount = 3
+	// viewerSampleRetentionDays is how long per-cycle viewer counts are kept for the /activity
+	// viewer chart. Stream sessions keep their peak and average viewers after samples are pruned.
+	viewerSampleRetentionDays = 90
+	// viewerSamplePruneInterval is how often expired viewer counts are deleted.
+	viewerSamplePruneInterval = 24 * time.Hour
+)
+
+// recordStreamHistory adds the current viewer count to the creator's stream session, or closes
+// the session once the stream ended.
+func (b *Bot) recordStreamHistory(creatorID string, streamInfo *api.StreamResponse, ended bool) {
+	stream := streamInfo.Response.Stream
+	now := time.Now().Unix()
+	switch {
+	case stream.Status == 2:
+		if err := b.Repo.RecordStreamSample(creatorID, stream.StartedAt/1000, stream.ViewerCount, now); err != nil {
+			log.Printf("Error recording stream of %s: %v", creatorID, err)
+		}
+	case ended:
+		if err := b.Repo.EndStreamSessions(creatorID, now); err != nil {
+			log.Printf("Error ending stream sessions of %s: %v", creatorID, err)
+		}
+	}
+}
+
+// endUntrackedStreams closes the open stream sessions of creators that are no longer monitored,
+// since no monitoring cycle will see their streams end.
+func (b *Bot) endUntrackedStreams(monitored map[string]*monitorJob) {
+	creatorIDs, err := b.Repo.GetOpenStreamSessionCreatorIDs()
+	if err != nil {
+		log.Printf("Error fetching open stream sessions: %v", err)
+		return
+	}
+	now := time.Now().Unix()
+	for _, creatorID := range creatorIDs {
+		if _, ok := monitored[creatorID]; ok {
+			continue
+		}
+		b.live.forget(creatorID)
+		if err := b.Repo.EndStreamSessions(creatorID, now); err != nil {
+			log.Printf("Error ending stream sessions of %s: %v", creatorID, err)
+		}
+	}
+}
+
+// recordPostHistory stores the posts of a creator's timeline that are not recorded yet.
+func (b *Bot) recordPostHistory(creatorID string, posts []api.Post) {
+	events := make([]models.PostEvent, len(posts))
+	for idx, post := range posts {
+		events[idx] = models.PostEvent{UserID: creatorID, PostID: post.ID, PostedAt: post.CreatedAt}
+	}
+	if err := b.Repo.RecordPostEvents(events); err != nil {
+		log.Printf("Error recording posts of %s: %v", creatorID, err)
+	}
+}
+
+func (b *Bot) pruneViewerSamplesPeriodically() {
+	ticker := time.NewTicker(viewerSamplePruneInterval)
+	defer ticker.Stop()
+
+	for {
+		b.pruneViewerSamples()
+
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneViewerSamples deletes viewer counts older than viewerSampleRetentionDays.
+func (b *Bot) pruneViewerSamples() {
+	cutoff := time.Now().AddDate(0, 0, -viewerSampleRetentionDays).Unix()
+	deleted, err := b.Repo.DeleteStreamViewerSamplesBefore(cutoff)
+	if err != nil {
+		log.Printf("Error pruning stream viewer samples: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Pruned %d stream viewer samples older than %d days", deleted, viewerSampleRetentionDays)
+	}
+}
+
+// creatorStats computes a creator's statistics over the last days days, or all recorded history if days is 0.
+func (b *Bot) creatorStats(user *models.MonitoredUser, days int, loc *time.Location) (embed.CreatorStats, error) {
+	now := time.Now()
+	var since int64
+	if days > 0 {
+		since = now.AddDate(0, 0, -days).Unix()
+	}
+
+	posts, err := b.Repo.GetPostEvents(user.UserID, since)
+	if err != nil {
+		return embed.CreatorStats{}, err
+	}
+	sessions, err := b.Repo.GetStreamSessions(user.UserID, since)
+	if err != nil {
+		return embed.CreatorStats{}, err
+	}
+
+	stats := embed.CreatorStats{Username: user.Username, Timezone: loc.String(), Posts: len(posts), Streams: len(sessions)}
+
+	first := now.Unix()
+	if len(posts) > 0 {
+		first = min(first, posts[0].PostedAt)
+	}
+	if len(sessions) > 0 {
+		first = min(first, sessions[0].StartedAt)
+	}
+	if len(posts) > 0 || len(sessions) > 0 {
+		stats.Since = first
+	}
+
+	weeks := float64(days) / 7
+	if days == 0 {
+		weeks = max(now.Sub(time.Unix(first, 0)).Hours()/(24*7), 1)
+	}
+	stats.PostsPerWeek = float64(len(posts)) / weeks
+
+	var hourCounts [24]int
+	for _, post := range posts {
+		hourCounts[time.Unix(post.PostedAt, 0).In(loc).Hour()]++
+	}
+	hours := make([]int, 0, 24)
+	for hour, count := range hourCounts {
+		if count > 0 {
+			hours = append(hours, hour)
+		}
+	}
+	sort.SliceStable(hours, func(a, c int) bool { return hourCounts[hours[a]] > hourCounts[hours[c]] })
+	stats.TopHours = hours[:min(len(hours), typicalHourCount)]
+
+	if len(sessions) > 0 {
+		var totalDuration time.Duration
+		var totalViewers int
+		for _, session := range sessions {
+			end := session.EndedAt
+			if end == 0 {
+				end = now.Unix()
+			}
+			totalDuration += time.Duration(end-session.StartedAt) * time.Second
+			totalViewers += session.AverageViewers()
+			stats.PeakViewers = max(stats.PeakViewers, session.PeakViewers)
+		}
+		stats.AverageDuration = totalDuration / time.Duration(len(sessions))
+		stats.AverageViewers = totalViewers / len(sessions)
+	}
+	return stats, nil
+}
+
+func (b *Bot) handleCreatorStatsCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	username := options[0].StringValue()
+	period := "30d"
+	if len(options) > 1 {
+		period = options[1].StringValue()
+	}
+
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || user == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Creator **%s** is not being monitored in this server.", username))
+		return
+	}
+
+	settings, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		settings = &models.GuildSettings{GuildID: i.GuildID}
+	}
+
+	stats, err := b.creatorStats(user, statsPeriods[period], guildLocation(settings))
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching statistics: %v", err))
+		return
+	}
+	stats.Period = describeStatsPeriod(period)
+
+	embedMsg := embed.CreateCreatorStatsEmbed(stats)
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embedMsg},
+	})
+	if err != nil {
+		log.Printf("Error sending creator stats: %v", err)
+	}
+}
+
+func describeStatsPeriod(period string) string {
+	if days := statsPeriods[period]; days > 0 {
+		return fmt.Sprintf("Last %d days", days)
+	}
+	return "All time"
+}
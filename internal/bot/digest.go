@@ -0,0 +1,261 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/api"
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	digestDaily  = "daily"
+	digestWeekly = "weekly"
+
+	postDeliveryImmediate = "immediate"
+	postDeliveryDigest    = "digest"
+
+	// digestExcerptLength is how much of a post's text is shown in the digest.
+	digestExcerptLength = 80
+	// digestCheckInterval is how often guilds are checked for a due digest.
+	digestCheckInterval = time.Minute
+)
+
+// usesDigest reports whether a creator's posts go to the guild's digest instead of being sent right away.
+func usesDigest(settings *models.GuildSettings, user models.MonitoredUser) bool {
+	if settings.DigestSchedule == "" {
+		return false
+	}
+	switch user.PostDelivery {
+	case postDeliveryDigest:
+		return true
+	case postDeliveryImmediate:
+		return false
+	default:
+		return settings.DigestAllCreators
+	}
+}
+
+// queueDigestPost stores a post for the guild's next digest if the creator uses it, and reports whether it did.
+// The configured channel is stored; forum threads are resolved when the digest is sent.
+func (b *Bot) queueDigestPost(user models.MonitoredUser, post api.Post) bool {
+	settings, err := b.Repo.GetGuildSettings(user.GuildID)
+	if err != nil {
+		log.Printf("Could not fetch settings for guild %s, sending post immediately: %v", user.GuildID, err)
+		return false
+	}
+	if !usesDigest(settings, user) {
+		return false
+	}
+
+	excerpt := strings.Join(strings.Fields(post.Content), " ")
+	excerpt = strings.NewReplacer("[", "(", "]", ")").Replace(excerpt)
+	err = b.Repo.AddDigestItem(&models.DigestItem{
+		GuildID:   user.GuildID,
+		ChannelID: configuredChannel(&user, deliveryPosts),
+		CreatorID: user.UserID,
+		Username:  user.Username,
+		PostID:    post.ID,
+		Excerpt:   embed.Truncate(excerpt, digestExcerptLength),
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		log.Printf("Error queueing post %s of %s for the digest in guild %s, sending it immediately: %v", post.ID, user.Username, user.GuildID, err)
+		return false
+	}
+	return true
+}
+
+// lastDigestDue returns the most recent scheduled digest time at or before now.
+func lastDigestDue(settings *models.GuildSettings, now time.Time) time.Time {
+	minutes, ok := parseClock(settings.DigestTime)
+	if !ok {
+		minutes = 9 * 60
+	}
+
+	loc := guildLocation(settings)
+	local := now.In(loc)
+	due := time.Date(local.Year(), local.Month(), local.Day(), minutes/60, minutes%60, 0, 0, loc)
+	if settings.DigestSchedule == digestWeekly {
+		due = due.AddDate(0, 0, -((int(local.Weekday()) - settings.DigestWeekday + 7) % 7))
+		if due.After(local) {
+			due = due.AddDate(0, 0, -7)
+		}
+	} else if due.After(local) {
+		due = due.AddDate(0, 0, -1)
+	}
+	return due
+}
+
+func (b *Bot) sendDigestsPeriodically() {
+	ticker := time.NewTicker(digestCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		b.sendDueDigests()
+
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendDueDigests posts the digest of every guild whose scheduled time has passed. Posts queued
+// before a guild turned digests off are flushed right away.
+func (b *Bot) sendDueDigests() {
+	guildIDs, err := b.Repo.GetGuildsWithDigestItems()
+	if err != nil {
+		log.Printf("[Digest] Error fetching guilds with queued posts: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, guildID := range guildIDs {
+		settings, err := b.Repo.GetGuildSettings(guildID)
+		if err != nil {
+			log.Printf("[Digest] Could not fetch settings for guild %s: %v", guildID, err)
+			continue
+		}
+		if settings.DigestSchedule != "" && settings.LastDigestAt >= lastDigestDue(settings, now).Unix() {
+			continue
+		}
+
+		items, err := b.Repo.GetDigestItems(guildID)
+		if err != nil {
+			log.Printf("[Digest] Error fetching queued posts for guild %s: %v", guildID, err)
+			continue
+		}
+		if !b.sendDigest(guildID, settings, items) {
+			// Unsent posts stay queued and the digest is retried on the next check.
+			continue
+		}
+
+		if err := b.Repo.UpdateGuildSettings(guildID, map[string]any{"last_digest_at": now.Unix()}); err != nil {
+			log.Printf("[Digest] Error saving digest time for guild %s: %v", guildID, err)
+		}
+	}
+}
+
+// sendDigest posts one summary per channel and removes the posts it covered. It reports whether
+// every channel's summary was sent.
+func (b *Bot) sendDigest(guildID string, settings *models.GuildSettings, items []models.DigestItem) bool {
+	title := "Daily Digest"
+	switch settings.DigestSchedule {
+	case digestWeekly:
+		title = "Weekly Digest"
+	case "":
+		title = "Post Digest"
+	}
+
+	var channelOrder []string
+	byChannel := make(map[string][]models.DigestItem)
+	for _, item := range b.resolveDigestChannels(guildID, items) {
+		if _, ok := byChannel[item.ChannelID]; !ok {
+			channelOrder = append(channelOrder, item.ChannelID)
+		}
+		byChannel[item.ChannelID] = append(byChannel[item.ChannelID], item)
+	}
+
+	// Only posts that were sent are removed; the rest stay queued for the next digest.
+	var sentIDs []uint
+	complete := true
+	for _, channelID := range channelOrder {
+		for _, page := range embed.CreateDigestEmbeds(title, byChannel[channelID], 0) {
+			sent, err := b.Session.ChannelMessageSendEmbed(channelID, page.Embed)
+			if err != nil {
+				log.Printf("[Digest] Error sending digest to channel %s in guild %s: %v", channelID, guildID, err)
+				if isUnknownChannel(err) {
+					// The channel is gone, so its posts can never be sent.
+					for _, item := range byChannel[channelID] {
+						sentIDs = append(sentIDs, item.ID)
+					}
+				} else {
+					complete = false
+				}
+				break
+			}
+			b.publishIfAnnouncement(settings, sent)
+			go b.Repo.IncrementPostCount()
+			for _, item := range page.Items {
+				sentIDs = append(sentIDs, item.ID)
+			}
+		}
+	}
+
+	if err := b.Repo.DeleteDigestItems(sentIDs); err != nil {
+		log.Printf("[Digest] Error deleting sent posts for guild %s: %v", guildID, err)
+	}
+	return complete
+}
+
+// resolveDigestChannels replaces the channel of each item with the creator's current delivery
+// channel, creating or reopening forum threads only now that the digest is sent.
+func (b *Bot) resolveDigestChannels(guildID string, items []models.DigestItem) []models.DigestItem {
+	resolved := make(map[string]string)
+	for idx, item := range items {
+		channelID, ok := resolved[item.CreatorID]
+		if !ok {
+			channelID = item.ChannelID
+			if user, err := b.Repo.GetMonitoredUser(guildID, item.CreatorID); err == nil && user != nil {
+				channelID = b.deliveryChannel(user, deliveryPosts)
+			}
+			resolved[item.CreatorID] = channelID
+		}
+		items[idx].ChannelID = channelID
+	}
+	return items
+}
+
+func (b *Bot) handleDigestCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	username := options[0].StringValue()
+	delivery := options[1].StringValue()
+	if delivery == "default" {
+		delivery = ""
+	}
+
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || user == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Creator **%s** is not being monitored in this server.", username))
+		return
+	}
+	if err := b.Repo.SetPostDeliveryByUsername(i.GuildID, username, delivery); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating post delivery: %v", err))
+		return
+	}
+
+	settings, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		settings = &models.GuildSettings{GuildID: i.GuildID}
+	}
+	updated := *user
+	updated.PostDelivery = delivery
+
+	msg := fmt.Sprintf("Posts from **%s** will be sent %s.", user.Username, describePostDelivery(settings, updated))
+	if delivery == postDeliveryDigest && settings.DigestSchedule == "" {
+		msg += "\nDigests are disabled in this server, so posts are still sent immediately. Use `/config digest` to set a schedule."
+	}
+	b.editInteractionResponse(s, i, msg)
+	b.recordAudit(s, i, user.Username, describePostDelivery(settings, *user), describePostDelivery(settings, updated))
+}
+
+func describePostDelivery(settings *models.GuildSettings, user models.MonitoredUser) string {
+	if usesDigest(settings, user) {
+		return "in the digest"
+	}
+	return "immediately"
+}
@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+)
+
+func TestLastDigestDue(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, loc)
+	}
+	daily := models.GuildSettings{Timezone: "America/New_York", DigestSchedule: digestDaily, DigestTime: "09:00"}
+	weekly := func(weekday time.Weekday) models.GuildSettings {
+		return models.GuildSettings{Timezone: "America/New_York", DigestSchedule: digestWeekly, DigestTime: "09:00", DigestWeekday: int(weekday)}
+	}
+
+	tests := []struct {
+		name     string
+		settings models.GuildSettings
+		now      time.Time
+		want     time.Time
+	}{
+		{name: "daily after the digest time", settings: daily, now: at(2025, 6, 4, 10, 0), want: at(2025, 6, 4, 9, 0)},
+		{name: "daily at the digest time", settings: daily, now: at(2025, 6, 4, 9, 0), want: at(2025, 6, 4, 9, 0)},
+		{name: "daily before the digest time", settings: daily, now: at(2025, 6, 4, 8, 59), want: at(2025, 6, 3, 9, 0)},
+		{name: "daily across the new year", settings: daily, now: at(2026, 1, 1, 8, 0), want: at(2025, 12, 31, 9, 0)},
+		{
+			name:     "invalid time defaults to 09:00",
+			settings: models.GuildSettings{Timezone: "America/New_York", DigestSchedule: digestDaily, DigestTime: "nine"},
+			now:      at(2025, 6, 4, 10, 0),
+			want:     at(2025, 6, 4, 9, 0),
+		},
+		{name: "weekly later in the week", settings: weekly(time.Monday), now: at(2025, 6, 4, 10, 0), want: at(2025, 6, 2, 9, 0)},
+		{name: "weekly on the day after the digest time", settings: weekly(time.Monday), now: at(2025, 6, 2, 9, 30), want: at(2025, 6, 2, 9, 0)},
+		{name: "weekly on the day before the digest time", settings: weekly(time.Monday), now: at(2025, 6, 2, 8, 0), want: at(2025, 5, 26, 9, 0)},
+		{name: "weekly on Sunday seen from Saturday", settings: weekly(time.Sunday), now: at(2025, 6, 7, 23, 0), want: at(2025, 6, 1, 9, 0)},
+		{name: "weekly on Saturday seen from Sunday", settings: weekly(time.Saturday), now: at(2025, 6, 8, 1, 0), want: at(2025, 6, 7, 9, 0)},
+		{name: "daily on the day the clocks spring forward", settings: daily, now: at(2025, 3, 9, 12, 0), want: at(2025, 3, 9, 9, 0)},
+		{name: "daily before the digest time after springing forward", settings: daily, now: at(2025, 3, 9, 8, 0), want: at(2025, 3, 8, 9, 0)},
+		{name: "weekly from the week before the clocks spring forward", settings: weekly(time.Monday), now: at(2025, 3, 10, 8, 0), want: at(2025, 3, 3, 9, 0)},
+		{name: "weekly from the week before the clocks fall back", settings: weekly(time.Monday), now: at(2025, 11, 3, 8, 0), want: at(2025, 10, 27, 9, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lastDigestDue(&tt.settings, tt.now.UTC())
+			if !got.Equal(tt.want) {
+				t.Errorf("lastDigestDue() = %s, want %s", got, tt.want)
+			}
+			if local := got.In(loc); local.Hour() != 9 || local.Minute() != 0 {
+				t.Errorf("lastDigestDue() = %s, not at 09:00 local time", local)
+			}
+		})
+	}
+}
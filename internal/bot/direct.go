@@ -0,0 +1,285 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/api"
+	"github.com/NotiFansly/notifansly-bot/internal/config"
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// followsPerWindow uses of /follow per followWindow are allowed per Discord user, since every
+	// use looks the creator up on Fansly.
+	followsPerWindow = 5
+	followWindow     = 10 * time.Minute
+)
+
+// followLimiter rate limits /follow per Discord user.
+type followLimiter struct {
+	mu   sync.Mutex
+	uses map[string][]time.Time
+}
+
+func newFollowLimiter() *followLimiter {
+	return &followLimiter{uses: make(map[string][]time.Time)}
+}
+
+// allow reports whether userID may use /follow now, and counts the use if so.
+func (l *followLimiter) allow(userID string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := l.uses[userID][:0]
+	for _, usedAt := range l.uses[userID] {
+		if now.Sub(usedAt) < followWindow {
+			recent = append(recent, usedAt)
+		}
+	}
+	if len(recent) >= followsPerWindow {
+		l.uses[userID] = recent
+		return false
+	}
+	l.uses[userID] = append(recent, now)
+	return true
+}
+
+// handleFollowCommand subscribes the caller to DM notifications for a creator.
+func (b *Bot) handleFollowCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	caller := interactionUser(i)
+	if caller == nil {
+		return
+	}
+	if !b.follows.allow(caller.ID, time.Now()) {
+		b.editInteractionResponse(s, i, fmt.Sprintf("You can use `/follow` at most %d times per %s. Please try again later.", followsPerWindow, followWindow))
+		return
+	}
+
+	var username string
+	notifType := "all"
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "username":
+			username = extractUsernameFromURL(opt.StringValue())
+		case "type":
+			notifType = opt.StringValue()
+		}
+	}
+
+	accountInfo, err := b.APIPool.GetAccountInfo(b.ctx, username)
+	if errors.Is(err, api.ErrNotFound) || (err == nil && accountInfo == nil) {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Creator **%s** was not found on Fansly. Please check the username.", username))
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting account info for %s: %v", username, err)
+		b.editInteractionResponse(s, i, "Error fetching account info. Fansly might be unavailable, please try again later.")
+		return
+	}
+
+	existing, err := b.Repo.GetDirectSubscription(caller.ID, accountInfo.ID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error checking your subscriptions: %v", err))
+		return
+	}
+	if existing == nil {
+		count, err := b.Repo.CountDirectSubscriptions(caller.ID)
+		if err != nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error checking your subscriptions: %v", err))
+			return
+		}
+		if count >= int64(config.MaxFollowsPerUser) {
+			b.editInteractionResponse(s, i, fmt.Sprintf("You can follow at most %d creators. Use `/unfollow` to make room.", config.MaxFollowsPerUser))
+			return
+		}
+	}
+
+	sub := &models.DirectSubscription{
+		DiscordUserID:  caller.ID,
+		CreatorID:      accountInfo.ID,
+		Username:       username,
+		AvatarLocation: accountInfo.AvatarURL(),
+		PostsEnabled:   notifType != "live",
+		LiveEnabled:    notifType != "posts",
+		CreatedAt:      time.Now().Unix(),
+	}
+	if existing != nil {
+		sub.LastPostID = existing.LastPostID
+		sub.LastStreamStart = existing.LastStreamStart
+		sub.CreatedAt = existing.CreatedAt
+	}
+
+	var note string
+	if sub.PostsEnabled {
+		// Anyone can run /follow, so unlike /add it never makes the bot's Fansly accounts follow a creator.
+		timelinePosts, timelineErr := b.APIPool.GetTimelinePost(b.ctx, accountInfo.ID)
+		switch {
+		case timelineErr != nil:
+			sub.PostsEnabled = false
+			sub.LiveEnabled = true
+			note = "\nTheir timeline is not accessible, so you will only be notified when they go live."
+		case existing == nil && len(timelinePosts) > 0:
+			// Start from the latest post so following doesn't DM an old post.
+			sub.LastPostID = timelinePosts[0].ID
+		}
+	}
+
+	if err := b.Repo.UpsertDirectSubscription(sub); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error saving your subscription: %v", err))
+		return
+	}
+
+	b.accounts.add(accountInfo.ID, username, accountInfo.DisplayName)
+	b.editInteractionResponse(s, i, fmt.Sprintf("✅ You will get a DM for %s from **%s**.%s\nMake sure you allow direct messages from the bot.", directSubscriptionTypes(sub), username, note))
+}
+
+func (b *Bot) handleUnfollowCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	caller := interactionUser(i)
+	if caller == nil {
+		return
+	}
+
+	username := extractUsernameFromURL(i.ApplicationCommandData().Options[0].StringValue())
+	if err := b.Repo.DeleteDirectSubscriptionByUsername(caller.ID, username); err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Could not unfollow **%s**: %v", username, err), true)
+		return
+	}
+	b.respondToInteraction(s, i, fmt.Sprintf("You will no longer get DMs for **%s**.", username), true)
+}
+
+func (b *Bot) handleFollowingCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	caller := interactionUser(i)
+	if caller == nil {
+		return
+	}
+
+	subs, err := b.Repo.GetDirectSubscriptionsForUser(caller.ID)
+	if err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Error fetching your subscriptions: %v", err), true)
+		return
+	}
+	if len(subs) == 0 {
+		b.respondToInteraction(s, i, "You are not following any creators. Use `/follow` to get DM notifications.", true)
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**You follow %d/%d creators:**\n", len(subs), config.MaxFollowsPerUser)
+	paused := false
+	for _, sub := range subs {
+		fmt.Fprintf(&sb, "- **%s**: %s", sub.Username, directSubscriptionTypes(&sub))
+		if sub.Paused {
+			sb.WriteString(" (paused)")
+			paused = true
+		}
+		sb.WriteString("\n")
+	}
+	if paused {
+		sb.WriteString("\nPaused subscriptions stopped because the bot could not DM you. Open your DMs and `/follow` again to resume.")
+	}
+	b.respondToInteraction(s, i, sb.String(), true)
+}
+
+// followedCreators suggests the creators a user follows, for /unfollow autocomplete.
+func (b *Bot) followedCreators(user *discordgo.User, query string) []creatorSuggestion {
+	if user == nil {
+		return nil
+	}
+	subs, err := b.Repo.GetDirectSubscriptionsForUser(user.ID)
+	if err != nil {
+		log.Printf("Error fetching direct subscriptions for autocomplete for user %s: %v", user.ID, err)
+		return nil
+	}
+	candidates := make([]creatorSuggestion, len(subs))
+	for idx, sub := range subs {
+		candidates[idx] = creatorSuggestion{Username: sub.Username}
+	}
+	return matchCreators(candidates, query)
+}
+
+func directSubscriptionTypes(sub *models.DirectSubscription) string {
+	switch {
+	case sub.PostsEnabled && sub.LiveEnabled:
+		return "new posts and live streams"
+	case sub.PostsEnabled:
+		return "new posts"
+	default:
+		return "live streams"
+	}
+}
+
+// notifyDirectLive DMs subscribers whose last seen stream is older than the current one.
+func (b *Bot) notifyDirectLive(subs []models.DirectSubscription, streamInfo *api.StreamResponse) {
+	stream := streamInfo.Response.Stream
+	if stream.Status != 2 {
+		return
+	}
+	for _, sub := range subs {
+		if stream.StartedAt <= sub.LastStreamStart {
+			continue
+		}
+		if err := b.Repo.UpdateDirectLastStreamStart(sub.DiscordUserID, sub.CreatorID, stream.StartedAt); err != nil {
+			log.Printf("Error updating last stream start for DM subscription %s/%s: %v", sub.DiscordUserID, sub.Username, err)
+			continue
+		}
+		embedMsg := embed.CreateLiveStreamEmbed(sub.Username, streamInfo, sub.AvatarLocation, "", 0)
+		b.sendDirectNotification(sub, fmt.Sprintf("**%s** is live!", sub.Username), embedMsg)
+	}
+}
+
+// notifyDirectPost DMs subscribers who have not seen the creator's latest post yet.
+func (b *Bot) notifyDirectPost(subs []models.DirectSubscription, post api.Post) {
+	for _, sub := range subs {
+		if post.ID == sub.LastPostID {
+			continue
+		}
+		if err := b.Repo.UpdateDirectLastPostID(sub.DiscordUserID, sub.CreatorID, post.ID); err != nil {
+			log.Printf("Error updating last post ID for DM subscription %s/%s: %v", sub.DiscordUserID, sub.Username, err)
+			continue
+		}
+		embedMsg := embed.CreatePostEmbed(sub.Username, post, sub.AvatarLocation, nil, 0)
+		b.sendDirectNotification(sub, fmt.Sprintf("**%s** has a new post!", sub.Username), embedMsg)
+	}
+}
+
+// sendDirectNotification DMs a subscriber, pausing all of their subscriptions if their DMs are closed.
+func (b *Bot) sendDirectNotification(sub models.DirectSubscription, content string, embedMsg *discordgo.MessageEmbed) {
+	channel, err := b.Session.UserChannelCreate(sub.DiscordUserID)
+	if err == nil {
+		_, err = b.Session.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+			Content: content,
+			Embed:   embedMsg,
+		})
+	}
+	if err == nil {
+		return
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeCannotSendMessagesToThisUser {
+		log.Printf("DMs closed for user %s, pausing their subscriptions", sub.DiscordUserID)
+		if pauseErr := b.Repo.PauseDirectSubscriptions(sub.DiscordUserID); pauseErr != nil {
+			log.Printf("Error pausing DM subscriptions for user %s: %v", sub.DiscordUserID, pauseErr)
+		}
+		return
+	}
+	log.Printf("Error sending DM notification for %s to user %s: %v", sub.Username, sub.DiscordUserID, err)
+}
@@ -0,0 +1,297 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+func (b *Bot) handleConfigCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	subcommand := i.ApplicationCommandData().Options[0]
+	switch subcommand.Name {
+	case "renames":
+		b.handleConfigRenames(s, i, subcommand.Options)
+	case "auditchannel":
+		b.handleConfigAuditChannel(s, i, subcommand.Options)
+	case "timezone":
+		b.handleConfigTimezone(s, i, subcommand.Options)
+	case "quiethours":
+		b.handleConfigQuietHours(s, i, subcommand.Options)
+	case "digest":
+		b.handleConfigDigest(s, i, subcommand.Options)
+	case "autopublish":
+		b.handleConfigAutoPublish(s, i, subcommand.Options)
+	case "liveevents":
+		b.handleConfigLiveEvents(s, i, subcommand.Options)
+	default:
+		b.editInteractionResponse(s, i, "Unknown setting.")
+	}
+}
+
+func (b *Bot) handleConfigRenames(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	enabled := options[0].BoolValue()
+
+	previous, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching settings: %v", err))
+		return
+	}
+
+	if err := b.Repo.UpdateGuildSettings(i.GuildID, map[string]any{"announce_renames": enabled}); err != nil {
+		log.Printf("Error updating rename announcements for guild %s: %v", i.GuildID, err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating settings: %v", err))
+		return
+	}
+
+	if enabled {
+		b.editInteractionResponse(s, i, "✅ Username changes of monitored creators will now be announced in their post notification channel.")
+	} else {
+		b.editInteractionResponse(s, i, "Username changes of monitored creators will no longer be announced.")
+	}
+	b.recordAudit(s, i, "", enabledText(previous.AnnounceRenames), enabledText(enabled))
+}
+
+func (b *Bot) handleConfigAuditChannel(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	var channelID string
+	if len(options) > 0 {
+		channelID = options[0].ChannelValue(s).ID
+	}
+
+	previous, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching settings: %v", err))
+		return
+	}
+
+	if err := b.Repo.UpdateGuildSettings(i.GuildID, map[string]any{"audit_channel_id": channelID}); err != nil {
+		log.Printf("Error updating audit channel for guild %s: %v", i.GuildID, err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating settings: %v", err))
+		return
+	}
+
+	if channelID != "" {
+		b.editInteractionResponse(s, i, fmt.Sprintf("✅ Configuration changes will now be posted in <#%s>.", channelID))
+	} else {
+		b.editInteractionResponse(s, i, "Configuration changes will no longer be posted. They are still available with `/auditlog`.")
+	}
+	b.recordAudit(s, i, "", channelText(previous.AuditChannelID), channelText(channelID))
+}
+
+func (b *Bot) handleConfigTimezone(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	zone := strings.TrimSpace(options[0].StringValue())
+	loc, err := time.LoadLocation(zone)
+	if err != nil || zone == "" || strings.EqualFold(zone, "local") {
+		b.editInteractionResponse(s, i, fmt.Sprintf("**%s** is not a known timezone. Use a name like `Europe/Berlin`, `America/New_York` or `UTC`.", zone))
+		return
+	}
+
+	previous, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching settings: %v", err))
+		return
+	}
+
+	if err := b.Repo.UpdateGuildSettings(i.GuildID, map[string]any{"timezone": loc.String()}); err != nil {
+		log.Printf("Error updating timezone for guild %s: %v", i.GuildID, err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating settings: %v", err))
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("✅ Server timezone set to **%s** (currently %s).", loc, time.Now().In(loc).Format("15:04")))
+	b.recordAudit(s, i, "", guildLocation(previous).String(), loc.String())
+}
+
+func (b *Bot) handleConfigQuietHours(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	var start, end string
+	mode := quietModeMute
+	for _, opt := range options {
+		switch opt.Name {
+		case "start":
+			start = strings.TrimSpace(opt.StringValue())
+		case "end":
+			end = strings.TrimSpace(opt.StringValue())
+		case "mode":
+			mode = opt.StringValue()
+		}
+	}
+
+	previous, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching settings: %v", err))
+		return
+	}
+
+	updated := *previous
+	if start == "" && end == "" {
+		updated.QuietHoursStart, updated.QuietHoursEnd, updated.QuietHoursMode = "", "", ""
+	} else {
+		startMinutes, okStart := parseClock(start)
+		endMinutes, okEnd := parseClock(end)
+		if !okStart || !okEnd {
+			b.editInteractionResponse(s, i, "Please give both a start and an end time as `HH:MM`, for example `23:00` and `07:30`.")
+			return
+		}
+		if startMinutes == endMinutes {
+			b.editInteractionResponse(s, i, "The start and end of quiet hours must be different.")
+			return
+		}
+		updated.QuietHoursStart = fmt.Sprintf("%02d:%02d", startMinutes/60, startMinutes%60)
+		updated.QuietHoursEnd = fmt.Sprintf("%02d:%02d", endMinutes/60, endMinutes%60)
+		updated.QuietHoursMode = mode
+	}
+
+	err = b.Repo.UpdateGuildSettings(i.GuildID, map[string]any{
+		"quiet_hours_start": updated.QuietHoursStart,
+		"quiet_hours_end":   updated.QuietHoursEnd,
+		"quiet_hours_mode":  updated.QuietHoursMode,
+	})
+	if err != nil {
+		log.Printf("Error updating quiet hours for guild %s: %v", i.GuildID, err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating settings: %v", err))
+		return
+	}
+
+	if updated.QuietHoursStart == "" {
+		b.editInteractionResponse(s, i, "Quiet hours are disabled. Any held notifications will be posted within a minute.")
+	} else {
+		msg := fmt.Sprintf("✅ Quiet hours set: %s.", describeQuietHours(&updated))
+		if updated.Timezone == "" {
+			msg += "\nTimes are in UTC. Use `/config timezone` to set the server's timezone."
+		}
+		b.editInteractionResponse(s, i, msg)
+	}
+	b.recordAudit(s, i, "", describeQuietHours(previous), describeQuietHours(&updated))
+}
+
+func (b *Bot) handleConfigDigest(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	previous, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching settings: %v", err))
+		return
+	}
+
+	updated := *previous
+	updated.DigestTime = "09:00"
+	updated.DigestWeekday = int(time.Monday)
+	for _, opt := range options {
+		switch opt.Name {
+		case "schedule":
+			updated.DigestSchedule = opt.StringValue()
+		case "time":
+			minutes, ok := parseClock(opt.StringValue())
+			if !ok {
+				b.editInteractionResponse(s, i, "Please give the digest time as `HH:MM`, for example `18:00`.")
+				return
+			}
+			updated.DigestTime = fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+		case "day":
+			updated.DigestWeekday = int(opt.IntValue())
+		case "all_creators":
+			updated.DigestAllCreators = opt.BoolValue()
+		}
+	}
+	if updated.DigestSchedule == "off" {
+		updated.DigestSchedule, updated.DigestTime, updated.DigestWeekday = "", "", 0
+	}
+
+	err = b.Repo.UpdateGuildSettings(i.GuildID, map[string]any{
+		"digest_schedule":     updated.DigestSchedule,
+		"digest_time":         updated.DigestTime,
+		"digest_weekday":      updated.DigestWeekday,
+		"digest_all_creators": updated.DigestAllCreators,
+		// The first digest covers posts from now on, not everything since the epoch.
+		"last_digest_at": time.Now().Unix(),
+	})
+	if err != nil {
+		log.Printf("Error updating digest settings for guild %s: %v", i.GuildID, err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating settings: %v", err))
+		return
+	}
+
+	if updated.DigestSchedule == "" {
+		b.editInteractionResponse(s, i, "Digests are disabled. Post notifications are sent immediately again, and any queued posts will be sent within a minute.")
+	} else {
+		msg := fmt.Sprintf("✅ Digest set: %s.", describeDigest(&updated))
+		if updated.DigestAllCreators {
+			msg += "\nPosts of all creators go to the digest. Use `/digest` to send a creator's posts immediately instead."
+		} else {
+			msg += "\nUse `/digest` to choose which creators' posts go to the digest."
+		}
+		b.editInteractionResponse(s, i, msg)
+	}
+	b.recordAudit(s, i, "", describeDigest(previous), describeDigest(&updated))
+}
+
+// describeDigest summarizes a guild's digest schedule, for responses and audit entries.
+func describeDigest(settings *models.GuildSettings) string {
+	if settings.DigestSchedule == "" {
+		return "disabled"
+	}
+	when := fmt.Sprintf("daily at %s", settings.DigestTime)
+	if settings.DigestSchedule == digestWeekly {
+		when = fmt.Sprintf("every %s at %s", time.Weekday(settings.DigestWeekday), settings.DigestTime)
+	}
+	scope := "selected creators"
+	if settings.DigestAllCreators {
+		scope = "all creators"
+	}
+	return fmt.Sprintf("%s %s, %s", when, guildLocation(settings), scope)
+}
+
+func (b *Bot) handleConfigAutoPublish(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	enabled := options[0].BoolValue()
+
+	previous, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching settings: %v", err))
+		return
+	}
+
+	if err := b.Repo.UpdateGuildSettings(i.GuildID, map[string]any{"auto_publish": enabled}); err != nil {
+		log.Printf("Error updating auto-publish for guild %s: %v", i.GuildID, err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating settings: %v", err))
+		return
+	}
+
+	if enabled {
+		b.editInteractionResponse(s, i, fmt.Sprintf("✅ Notifications sent to announcement channels will now be published automatically. Discord allows %d publishes per channel per hour; further notifications are published as the limit allows.", publishesPerHour))
+	} else {
+		b.editInteractionResponse(s, i, "Notifications in announcement channels will no longer be published automatically.")
+	}
+	b.recordAudit(s, i, "", enabledText(previous.AutoPublish), enabledText(enabled))
+}
+
+func (b *Bot) handleConfigLiveEvents(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	enabled := options[0].BoolValue()
+
+	previous, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching settings: %v", err))
+		return
+	}
+
+	if err := b.Repo.UpdateGuildSettings(i.GuildID, map[string]any{"live_events": enabled}); err != nil {
+		log.Printf("Error updating live events for guild %s: %v", i.GuildID, err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating settings: %v", err))
+		return
+	}
+
+	if enabled {
+		b.editInteractionResponse(s, i, "✅ Live streams will now show up in the server's Events tab. Make sure the bot has the **Manage Events** permission.")
+	} else {
+		b.editInteractionResponse(s, i, "Live streams will no longer create events. Events of ongoing streams are still ended when the stream ends.")
+	}
+	b.recordAudit(s, i, "", enabledText(previous.LiveEvents), enabledText(enabled))
+}
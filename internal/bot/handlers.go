@@ -1,7 +1,7 @@
 package bot
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/NotiFansly/notifansly-bot/api"
 	"github.com/NotiFansly/notifansly-bot/internal/config"
 	"github.com/NotiFansly/notifansly-bot/internal/database"
 	"github.com/NotiFansly/notifansly-bot/internal/models"
@@ -32,7 +33,16 @@ func (b *Bot) isBotOwner(i *discordgo.InteractionCreate) bool {
 	if config.BotOwnerID == "" {
 		return false // Can't be the owner if the ID isn't configured
 	}
-	return i.Member.User.ID == config.BotOwnerID
+	user := interactionUser(i)
+	return user != nil && user.ID == config.BotOwnerID
+}
+
+// interactionUser returns the user behind an interaction, whether it came from a guild or a DM.
+func interactionUser(i *discordgo.InteractionCreate) *discordgo.User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+	return i.User
 }
 
 func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -48,7 +58,7 @@ func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 		}
 
 		// Second, handle general permission checks for non-owners
-		if !b.isBotOwner(i) && !b.hasAdminOrModPermissions(s, i) {
+		if !publicCommands[i.ApplicationCommandData().Name] && !b.isBotOwner(i) && !b.canRunCommand(s, i, i.ApplicationCommandData().Name) {
 			username := "User"
 			if i.User != nil {
 				username = i.User.Username
@@ -88,15 +98,46 @@ func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 			b.handleSetLimitCommand(s, i)
 		case "setformat":
 			b.handleSetFormatCommand(s, i)
+		case "config":
+			b.handleConfigCommand(s, i)
+		case "permissions":
+			b.handlePermissionsCommand(s, i)
+		case "auditlog":
+			b.handleAuditLogCommand(s, i)
+		case "addmany":
+			b.handleAddManyCommand(s, i)
+		case "settings":
+			b.handleSettingsCommand(s, i)
+		case "roles":
+			b.handleRolesCommand(s, i)
+		case "digest":
+			b.handleDigestCommand(s, i)
+		case "cooldown":
+			b.handleCooldownCommand(s, i)
+		case "statusboard":
+			b.handleStatusBoardCommand(s, i)
+		case "creatorstats":
+			b.handleCreatorStatsCommand(s, i)
+		case "activity":
+			b.handleActivityCommand(s, i)
+		case "calendar":
+			b.handleCalendarCommand(s, i)
+		case "follow":
+			b.handleFollowCommand(s, i)
+		case "unfollow":
+			b.handleUnfollowCommand(s, i)
+		case "following":
+			b.handleFollowingCommand(s, i)
 		}
 
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		b.handleAutocomplete(s, i)
+
 	case discordgo.InteractionMessageComponent:
-		// All button clicks and other components fall here.
+		b.handleComponent(s, i)
+
 	case discordgo.InteractionModalSubmit:
-		// Handle submissions from our new modal
-		if strings.HasPrefix(i.ModalSubmitData().CustomID, "format_modal_") {
-			b.handleFormatModalSubmit(s, i)
-		}
+		b.handleModalSubmit(s, i)
 	}
 }
 
@@ -119,14 +160,7 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 
 	username := extractUsernameFromURL(rawUsername)
 
-	guildLimit := config.MaxMonitoredUsersPerGuild
-	subscription, err := b.Repo.GetGuildSubscription(i.GuildID)
-	if err == nil && subscription != nil {
-		if time.Now().Unix() < subscription.ExpiresAt {
-			guildLimit = subscription.UserLimit
-		}
-	}
-
+	guildLimit := b.guildUserLimit(i.GuildID)
 	if guildLimit > 0 {
 		count, err := b.Repo.CountMonitoredUsersForGuild(i.GuildID)
 		if err != nil {
@@ -138,12 +172,7 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 		if count >= int64(guildLimit) {
 			existingUser, _ := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
 			if existingUser == nil {
-				message := fmt.Sprintf(
-					"This server is at its limit of %d monitored creators. If your subscription has expired, you can manage your plan here: <https://notifansly.xyz/dashboard/server/%s/billing>",
-					guildLimit,
-					i.GuildID,
-				)
-				b.respondToInteraction(s, i, message, true)
+				b.respondToInteraction(s, i, guildLimitMessage(i.GuildID, guildLimit), true)
 				return
 			}
 		}
@@ -154,7 +183,7 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 		return
 	}
 
-	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 	})
 	if err != nil {
@@ -196,7 +225,11 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 			}
 		}
 
-		accountInfo, err := b.APIClient.GetAccountInfo(username)
+		accountInfo, err := b.APIPool.GetAccountInfo(b.ctx, username)
+		if errors.Is(err, api.ErrNotFound) {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Creator **%s** was not found on Fansly. Please check the username.", username))
+			return
+		}
 		if err != nil {
 			log.Printf("Error getting account info for %s: %v", username, err)
 			b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching account info: The user might not exist or Fansly API is unavailable. (%v)", err))
@@ -209,95 +242,46 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 			return
 		}
 
-		var avatarLocation string
-		if len(accountInfo.Avatar.Variants) > 0 && len(accountInfo.Avatar.Variants[0].Locations) > 0 {
-			avatarLocation = accountInfo.Avatar.Variants[0].Locations[0].Location
-		} else {
+		if accountInfo.AvatarURL() == "" {
 			log.Printf("Warning: No avatar found for user %s", username)
 		}
 
-		timelinePosts, timelineErr := b.APIClient.GetTimelinePost(accountInfo.ID)
+		var previousSettings string
+		if existing, _ := b.Repo.GetMonitoredUser(i.GuildID, accountInfo.ID); existing != nil {
+			previousSettings = describeMonitoredUser(existing)
+		}
+
+		timelinePosts, timelineErr := b.APIPool.GetTimelinePost(b.ctx, accountInfo.ID)
 		timelineAccessible := timelineErr == nil && len(timelinePosts) >= 0
 
 		if !timelineAccessible {
-			if myAccount, err := b.APIClient.GetMyAccountInfo(); err == nil && myAccount.ID != "" {
-				if following, err := b.APIClient.GetFollowing(myAccount.ID); err == nil {
-					isFollowing := false
-					for _, f := range following {
-						if f.AccountID == accountInfo.ID {
-							isFollowing = true
-							break
-						}
-					}
-					if !isFollowing {
-						if followErr := b.APIClient.FollowAccount(accountInfo.ID); followErr != nil {
-							log.Printf("Note: Could not automatically follow %s: %v", username, followErr)
-						}
-					}
-				}
+			if followErr := b.APIPool.EnsureFollowing(b.ctx, accountInfo.ID); followErr != nil {
+				log.Printf("Note: Could not automatically follow %s: %v", username, followErr)
 			}
-			timelinePosts, timelineErr = b.APIClient.GetTimelinePost(accountInfo.ID)
+			timelinePosts, timelineErr = b.APIPool.GetTimelinePost(b.ctx, accountInfo.ID)
 			timelineAccessible = timelineErr == nil
 		}
 
 		if !timelineAccessible {
-			b.editInteractionResponse(s, i, fmt.Sprintf("Cannot access timeline for **%s**. A confirmation message has been sent below.", username))
-
-			confirmMsgContent := fmt.Sprintf("%s, do you want to add **%s** for **live notifications only**? React with ✅ to confirm or ❌ to cancel.", i.Member.Mention(), username)
-			msg, err := s.ChannelMessageSend(i.ChannelID, confirmMsgContent)
-			if err != nil {
-				log.Printf("Error sending confirmation message: %v", err)
-				return
+			pending := &models.PendingAdd{
+				ID:               i.ID,
+				GuildID:          i.GuildID,
+				RequesterID:      i.Member.User.ID,
+				UserID:           accountInfo.ID,
+				Username:         username,
+				DisplayName:      accountInfo.DisplayName,
+				AvatarLocation:   accountInfo.AvatarURL(),
+				ChannelID:        channel.ID,
+				MentionRole:      mentionRole,
+				PreviousSettings: previousSettings,
+				ExpiresAt:        time.Now().Add(pendingAddTTL).Unix(),
 			}
-			s.MessageReactionAdd(i.ChannelID, msg.ID, "✅")
-			s.MessageReactionAdd(i.ChannelID, msg.ID, "❌")
-
-			ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-			defer cancel()
-
-			reactionChan := make(chan string)
-			handlerID := s.AddHandler(func(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
-				if r.MessageID != msg.ID || r.UserID == s.State.User.ID || r.UserID != i.Member.User.ID {
-					return
-				}
-				if r.Emoji.Name == "✅" || r.Emoji.Name == "❌" {
-					select {
-					case reactionChan <- r.Emoji.Name:
-					default:
-					}
-				}
-			})
-			defer handlerID()
-
-			select {
-			case reaction := <-reactionChan:
-				if reaction == "✅" {
-					user := &models.MonitoredUser{
-						GuildID: i.GuildID, UserID: accountInfo.ID, Username: username, NotificationChannel: channel.ID, PostNotificationChannel: channel.ID,
-						LiveNotificationChannel: channel.ID, LastPostID: "", LastStreamStart: 0, MentionRole: mentionRole, AvatarLocation: avatarLocation,
-						AvatarLocationUpdatedAt: time.Now().Unix(), LiveImageURL: "", PostsEnabled: false, LiveEnabled: true, LiveMentionRole: mentionRole, PostMentionRole: mentionRole,
-					}
-					if err := database.NewRepository().AddOrUpdateMonitoredUser(user); err != nil {
-						s.ChannelMessageEdit(i.ChannelID, msg.ID, fmt.Sprintf("Error adding user: %v", err))
-					} else {
-						s.ChannelMessageEdit(i.ChannelID, msg.ID, fmt.Sprintf("✅ Added **%s** for live notifications only.", username))
-					}
-				} else {
-					s.ChannelMessageEdit(i.ChannelID, msg.ID, "❌ Operation cancelled.")
-				}
-			case <-ctx.Done():
-				s.ChannelMessageEdit(i.ChannelID, msg.ID, "Confirmation timed out.")
-			}
-			time.AfterFunc(10*time.Second, func() { s.ChannelMessageDelete(i.ChannelID, msg.ID) })
+			b.requestLiveOnlyConfirmation(s, i, pending)
 			return
 		}
 
 		repo := database.NewRepository()
-		user := &models.MonitoredUser{
-			GuildID: i.GuildID, UserID: accountInfo.ID, Username: username, NotificationChannel: channel.ID, PostNotificationChannel: channel.ID,
-			LiveNotificationChannel: channel.ID, LastPostID: "", LastStreamStart: 0, MentionRole: mentionRole, AvatarLocation: avatarLocation,
-			AvatarLocationUpdatedAt: time.Now().Unix(), LiveImageURL: "", PostsEnabled: true, LiveEnabled: true, LiveMentionRole: mentionRole, PostMentionRole: mentionRole,
-		}
+		user := newMonitoredUser(i.GuildID, accountInfo, username, channel.ID, mentionRole, true)
 
 		err = repo.AddOrUpdateMonitoredUser(user)
 		if err != nil {
@@ -305,7 +289,10 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 			return
 		}
 
+		b.accounts.add(accountInfo.ID, username, accountInfo.DisplayName)
 		b.editInteractionResponse(s, i, fmt.Sprintf("Successfully added **%s** to the monitoring list for all notifications.", username))
+		b.recordAudit(s, i, username, previousSettings, describeMonitoredUser(user))
+		b.prepareDeliveryThreads(i.GuildID, accountInfo.ID)
 	}()
 }
 
@@ -328,7 +315,7 @@ func (b *Bot) handleSetColorCommand(s *discordgo.Session, i *discordgo.Interacti
 		return
 	}
 
-	colorInt, err := strconv.ParseInt(strings.TrimPrefix(colorHex, "#"), 16, 32)
+	colorInt, err := parseHexColor(colorHex)
 	if err != nil {
 		b.editInteractionResponse(s, i, "Could not parse the provided color. Please check the format.")
 		return
@@ -359,11 +346,14 @@ func (b *Bot) handleSetColorCommand(s *discordgo.Session, i *discordgo.Interacti
 		}
 	}
 
+	var oldColor int
 	switch notifType {
 	case "posts":
-		colors.PostEmbedColor = int(colorInt)
+		oldColor = colors.PostEmbedColor
+		colors.PostEmbedColor = colorInt
 	case "live":
-		colors.LiveEmbedColor = int(colorInt)
+		oldColor = colors.LiveEmbedColor
+		colors.LiveEmbedColor = colorInt
 	default:
 		b.editInteractionResponse(s, i, "Invalid notification type selected.")
 		return
@@ -382,6 +372,7 @@ func (b *Bot) handleSetColorCommand(s *discordgo.Session, i *discordgo.Interacti
 		strings.ToUpper(colorHex),
 	)
 	b.editInteractionResponse(s, i, responseMessage)
+	b.recordAudit(s, i, username, colorText(oldColor), colorText(colorInt))
 }
 
 func (b *Bot) handleServersCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -393,12 +384,21 @@ func (b *Bot) handleServersCommand(s *discordgo.Session, i *discordgo.Interactio
 		return
 	}
 
-	guilds := b.Session.State.Guilds
-	if len(guilds) == 0 {
-		b.editInteractionResponse(s, i, "The bot is not currently in any servers.")
-		return
+	requestedPage := 1
+	if len(i.ApplicationCommandData().Options) > 0 {
+		requestedPage = int(i.ApplicationCommandData().Options[0].IntValue())
+		requestedPage = max(1, requestedPage)
 	}
 
+	b.sendPagedList(s, i, "servers", listFilterAll, requestedPage)
+}
+
+// serverItems lists all guilds the bot is in, sorted by name.
+func (b *Bot) serverItems() []string {
+	b.Session.State.RLock()
+	guilds := append([]*discordgo.Guild(nil), b.Session.State.Guilds...)
+	b.Session.State.RUnlock()
+
 	sort.Slice(guilds, func(i, j int) bool {
 		return guilds[i].Name < guilds[j].Name
 	})
@@ -408,14 +408,7 @@ func (b *Bot) handleServersCommand(s *discordgo.Session, i *discordgo.Interactio
 		line := fmt.Sprintf("**%s**\n  `ID:` %s\n  `Members:` %d", guild.Name, guild.ID, guild.MemberCount)
 		serverDetails = append(serverDetails, line)
 	}
-
-	requestedPage := 1
-	if len(i.ApplicationCommandData().Options) > 0 {
-		requestedPage = int(i.ApplicationCommandData().Options[0].IntValue())
-		requestedPage = max(1, requestedPage)
-	}
-
-	b.sendPaginatedList(s, i, serverDetails, requestedPage)
+	return serverDetails
 }
 
 func (b *Bot) handleLeaveCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -465,6 +458,7 @@ func (b *Bot) handleRemoveCommand(s *discordgo.Session, i *discordgo.Interaction
 	username := i.ApplicationCommandData().Options[0].StringValue()
 
 	repo := database.NewRepository()
+	existing, _ := repo.GetMonitoredUserByUsername(i.GuildID, username)
 	err = repo.DeleteMonitoredUserByUsername(i.GuildID, username)
 	if err != nil {
 		b.editInteractionResponse(s, i, fmt.Sprintf("Error removing user: %v", err))
@@ -472,6 +466,13 @@ func (b *Bot) handleRemoveCommand(s *discordgo.Session, i *discordgo.Interaction
 	}
 
 	b.editInteractionResponse(s, i, fmt.Sprintf("Removed **%s** from the monitoring list.", username))
+	if existing != nil {
+		if existing.LiveEventID != "" {
+			b.completeLiveEvent(*existing)
+		}
+		b.deleteSubscriberRole(s, existing)
+		b.recordAudit(s, i, existing.Username, describeMonitoredUser(existing), "")
+	}
 }
 
 func (b *Bot) respondToInteraction(s *discordgo.Session, i *discordgo.InteractionCreate, content string, ephemeral bool) {
@@ -504,22 +505,29 @@ func (b *Bot) handleListCommand(s *discordgo.Session, i *discordgo.InteractionCr
 		requestedPage = max(1, requestedPage)
 	}
 
-	repo := database.NewRepository()
-	users, err := repo.GetMonitoredUsersForGuild(i.GuildID)
+	b.sendPagedList(s, i, "list", listFilterAll, requestedPage)
+}
+
+// monitoredUserItems lists the creators monitored in a guild that match filter.
+func (b *Bot) monitoredUserItems(guildID, filter string) ([]string, error) {
+	users, err := b.Repo.GetMonitoredUsersForGuild(guildID)
 	if err != nil {
-		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching monitored users: %v", err))
-		return
+		return nil, err
 	}
 
-	if len(users) == 0 {
-		b.editInteractionResponse(s, i, "No models are currently being monitored.")
-		return
+	settings, err := b.Repo.GetGuildSettings(guildID)
+	if err != nil {
+		settings = &models.GuildSettings{GuildID: guildID}
 	}
 
 	var monitoredUsers []string
 	for _, user := range users {
-		postChannelInfo := fmt.Sprintf("<#%s>", user.PostNotificationChannel)
-		liveChannelInfo := fmt.Sprintf("<#%s>", user.LiveNotificationChannel)
+		if !matchesListFilter(user, filter) {
+			continue
+		}
+
+		postChannelInfo := fmt.Sprintf("<#%s>", user.PostNotificationChannel) + b.publishWarning(settings, user.PostNotificationChannel)
+		liveChannelInfo := fmt.Sprintf("<#%s>", user.LiveNotificationChannel) + b.publishWarning(settings, user.LiveNotificationChannel)
 		roleInfoPost := getRoleName(user.PostMentionRole)
 		roleInfoLive := getRoleName(user.LiveMentionRole)
 
@@ -531,16 +539,21 @@ func (b *Bot) handleListCommand(s *discordgo.Session, i *discordgo.InteractionCr
 		if !user.LiveEnabled {
 			liveStatus = "❌ Disabled"
 		}
+		profileStatus := "✅ Enabled"
+		if !user.ProfileEnabled {
+			profileStatus = "❌ Disabled"
+		}
 
-		userInfo := fmt.Sprintf("- **%s**\n  • Posts: %s (in %s | Role: %s)\n  • Live: %s (in %s | Role: %s)",
+		userInfo := fmt.Sprintf("- **%s**\n  • Posts: %s (in %s | Role: %s)\n  • Live: %s (in %s | Role: %s)\n  • Profile changes: %s",
 			user.Username,
 			postStatus, postChannelInfo, roleInfoPost,
 			liveStatus, liveChannelInfo, roleInfoLive,
+			profileStatus,
 		)
 		monitoredUsers = append(monitoredUsers, userInfo)
 	}
 
-	b.sendPaginatedList(s, i, monitoredUsers, requestedPage)
+	return monitoredUsers, nil
 }
 
 func (b *Bot) handleSetLiveImageCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -569,6 +582,7 @@ func (b *Bot) handleSetLiveImageCommand(s *discordgo.Session, i *discordgo.Inter
 	}
 
 	repo := database.NewRepository()
+	existing, _ := repo.GetMonitoredUserByUsername(i.GuildID, username)
 	err = repo.UpdateLiveImageURL(i.GuildID, username, imageURL)
 	if err != nil {
 		log.Printf("Error updating live image URL: %v", err)
@@ -577,6 +591,9 @@ func (b *Bot) handleSetLiveImageCommand(s *discordgo.Session, i *discordgo.Inter
 	}
 
 	b.editInteractionResponse(s, i, fmt.Sprintf("Live image for **%s** has been set successfully.", username))
+	if existing != nil {
+		b.recordAudit(s, i, existing.Username, existing.LiveImageURL, imageURL)
+	}
 }
 
 func (b *Bot) handleToggleCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -594,6 +611,7 @@ func (b *Bot) handleToggleCommand(s *discordgo.Session, i *discordgo.Interaction
 	enabled := options[2].BoolValue()
 
 	repo := database.NewRepository()
+	existing, _ := repo.GetMonitoredUserByUsername(i.GuildID, username)
 	var updateErr error
 
 	switch notifiType {
@@ -609,6 +627,8 @@ func (b *Bot) handleToggleCommand(s *discordgo.Session, i *discordgo.Interaction
 		} else {
 			updateErr = repo.DisableLiveByUsername(i.GuildID, username)
 		}
+	case "profile":
+		updateErr = repo.SetProfileEnabledByUsername(i.GuildID, username, enabled)
 	default:
 		b.editInteractionResponse(s, i, "Invalid notification type selected.")
 		return
@@ -625,6 +645,19 @@ func (b *Bot) handleToggleCommand(s *discordgo.Session, i *discordgo.Interaction
 	}
 
 	b.editInteractionResponse(s, i, fmt.Sprintf("`%s` notifications have been **%s** for **%s**.", notifiType, status, username))
+	if existing != nil {
+		previous := existing.PostsEnabled
+		switch notifiType {
+		case "live":
+			previous = existing.LiveEnabled
+		case "profile":
+			previous = existing.ProfileEnabled
+		}
+		b.recordAudit(s, i, existing.Username, fmt.Sprintf("%s %s", notifiType, enabledText(previous)), fmt.Sprintf("%s %s", notifiType, status))
+		if notifiType == "live" && !enabled && existing.LiveEventID != "" {
+			b.endLiveEvent(*existing)
+		}
+	}
 }
 
 func (b *Bot) handleSetChannelCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -642,6 +675,7 @@ func (b *Bot) handleSetChannelCommand(s *discordgo.Session, i *discordgo.Interac
 	channel := options[2].ChannelValue(s)
 
 	repo := database.NewRepository()
+	existing, _ := repo.GetMonitoredUserByUsername(i.GuildID, username)
 	var updateErr error
 
 	switch notifType {
@@ -660,6 +694,14 @@ func (b *Bot) handleSetChannelCommand(s *discordgo.Session, i *discordgo.Interac
 	}
 
 	b.editInteractionResponse(s, i, fmt.Sprintf("Successfully set the %s notification channel for **%s** to %s.", notifType, username, channel.Mention()))
+	if existing != nil {
+		previous := existing.PostNotificationChannel
+		if notifType == "live" {
+			previous = existing.LiveNotificationChannel
+		}
+		b.recordAudit(s, i, existing.Username, channelText(previous), channel.Mention())
+		b.prepareDeliveryThreads(i.GuildID, existing.UserID)
+	}
 }
 
 func (b *Bot) handleSetPostMentionCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -685,6 +727,7 @@ func (b *Bot) handleSetPostMentionCommand(s *discordgo.Session, i *discordgo.Int
 	}
 
 	repo := database.NewRepository()
+	existing, _ := repo.GetMonitoredUserByUsername(i.GuildID, username)
 	err = repo.UpdatePostMentionRole(i.GuildID, username, roleID)
 	if err != nil {
 		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating post mention role: %v", err))
@@ -696,6 +739,9 @@ func (b *Bot) handleSetPostMentionCommand(s *discordgo.Session, i *discordgo.Int
 		message = fmt.Sprintf("Post mention role for **%s** set to %s.", username, roleMention)
 	}
 	b.editInteractionResponse(s, i, message)
+	if existing != nil {
+		b.recordAudit(s, i, existing.Username, roleText(existing.PostMentionRole), roleText(roleID))
+	}
 }
 
 func (b *Bot) handleSetLiveMentionCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -721,6 +767,7 @@ func (b *Bot) handleSetLiveMentionCommand(s *discordgo.Session, i *discordgo.Int
 	}
 
 	repo := database.NewRepository()
+	existing, _ := repo.GetMonitoredUserByUsername(i.GuildID, username)
 	err = repo.UpdateLiveMentionRole(i.GuildID, username, roleID)
 	if err != nil {
 		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating live mention role: %v", err))
@@ -732,6 +779,9 @@ func (b *Bot) handleSetLiveMentionCommand(s *discordgo.Session, i *discordgo.Int
 		message = fmt.Sprintf("Live mention role for **%s** set to %s.", username, roleMention)
 	}
 	b.editInteractionResponse(s, i, message)
+	if existing != nil {
+		b.recordAudit(s, i, existing.Username, roleText(existing.LiveMentionRole), roleText(roleID))
+	}
 }
 
 func (b *Bot) handleSetLimitCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -860,10 +910,13 @@ func (b *Bot) handleFormatModalSubmit(s *discordgo.Session, i *discordgo.Interac
 		}
 	}
 
+	var oldFormat string
 	// Correctly check for "posts" (plural)
 	if notifType == "posts" {
+		oldFormat = formats.PostMessageFormat
 		formats.PostMessageFormat = messageFormat
 	} else if notifType == "live" {
+		oldFormat = formats.LiveMessageFormat
 		formats.LiveMessageFormat = messageFormat
 	}
 
@@ -875,6 +928,54 @@ func (b *Bot) handleFormatModalSubmit(s *discordgo.Session, i *discordgo.Interac
 
 	responseMessage := fmt.Sprintf("✅ Successfully updated the **%s** notification message format.", notifType)
 	b.respondToInteraction(s, i, responseMessage, true)
+
+	target := userID
+	if user, _ := b.Repo.GetMonitoredUser(i.GuildID, userID); user != nil {
+		target = user.Username
+	}
+	b.recordAudit(s, i, target, oldFormat, messageFormat)
+}
+
+// guildUserLimit returns how many creators a guild may monitor, taking an active
+// subscription into account. Zero means unlimited.
+func (b *Bot) guildUserLimit(guildID string) int {
+	guildLimit := config.MaxMonitoredUsersPerGuild
+	subscription, err := b.Repo.GetGuildSubscription(guildID)
+	if err == nil && subscription != nil {
+		if time.Now().Unix() < subscription.ExpiresAt {
+			guildLimit = subscription.UserLimit
+		}
+	}
+	return guildLimit
+}
+
+// guildLimitMessage tells the user that the guild cannot monitor more creators.
+func guildLimitMessage(guildID string, guildLimit int) string {
+	return fmt.Sprintf(
+		"This server is at its limit of %d monitored creators. If your subscription has expired, you can manage your plan here: <https://notifansly.xyz/dashboard/server/%s/billing>",
+		guildLimit,
+		guildID,
+	)
+}
+
+// newMonitoredUser builds the row stored for a freshly added creator. Live notifications are
+// always enabled; posts only if the creator's timeline is accessible.
+func newMonitoredUser(guildID string, accountInfo *api.ModelAccountInfo, username, channelID, mentionRole string, postsEnabled bool) *models.MonitoredUser {
+	return &models.MonitoredUser{
+		GuildID: guildID, UserID: accountInfo.ID, Username: username, DisplayName: accountInfo.DisplayName, NotificationChannel: channelID, PostNotificationChannel: channelID,
+		LiveNotificationChannel: channelID, LastPostID: "", LastStreamStart: 0, MentionRole: mentionRole, AvatarLocation: accountInfo.AvatarURL(),
+		AvatarLocationUpdatedAt: time.Now().Unix(), LiveImageURL: "", PostsEnabled: postsEnabled, LiveEnabled: true, LiveMentionRole: mentionRole, PostMentionRole: mentionRole,
+	}
+}
+
+// parseHexColor parses a color in #RRGGBB or #RGB notation.
+func parseHexColor(colorHex string) (int, error) {
+	hex := strings.TrimPrefix(colorHex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	color, err := strconv.ParseInt(hex, 16, 32)
+	return int(color), err
 }
 
 func getRoleName(roleID string) string {
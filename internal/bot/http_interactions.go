@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/config"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxInteractionBodySize bounds the request body read before the signature is verified.
+const maxInteractionBodySize = 1 << 20
+
+// startHTTPServer starts the optional HTTP server when HTTP_LISTEN_ADDR is configured.
+// Interactions received there are verified with PUBLIC_KEY and dispatched to the same
+// handlers as gateway interactions. Calendar feeds are served too when PUBLIC_URL is set.
+func (b *Bot) startHTTPServer() error {
+	if config.HTTPListenAddr == "" {
+		return nil
+	}
+
+	publicKey, err := interactionsPublicKey()
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(config.InteractionsPath, interactionsHandler(publicKey, func(i *discordgo.InteractionCreate) {
+		b.interactionCreate(b.Session, i)
+	}))
+	if calendarFeedsEnabled() {
+		mux.Handle(config.CalendarPath+"/", b.calendarHandler())
+	}
+
+	b.httpServer = &http.Server{
+		Addr:              config.HTTPListenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Printf("HTTP server listening on %s (interactions at %s)", config.HTTPListenAddr, config.InteractionsPath)
+		if err := b.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("HTTP server error: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// interactionsPublicKey decodes PUBLIC_KEY, which verifies requests to the interactions endpoint.
+func interactionsPublicKey() (ed25519.PublicKey, error) {
+	publicKey, err := hex.DecodeString(config.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("PUBLIC_KEY must be the hex encoded Ed25519 key from the Discord developer portal")
+	}
+	return ed25519.PublicKey(publicKey), nil
+}
+
+// calendarFeedsEnabled reports whether startHTTPServer serves calendar feeds: the HTTP server
+// must be configured and able to start, and PUBLIC_URL must be set.
+func calendarFeedsEnabled() bool {
+	if config.HTTPListenAddr == "" || config.PublicURL == "" {
+		return false
+	}
+	_, err := interactionsPublicKey()
+	return err == nil
+}
+
+func (b *Bot) stopHTTPServer() {
+	if b.httpServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := b.httpServer.Shutdown(ctx); err != nil {
+		log.Printf("Error shutting down HTTP server: %v", err)
+	}
+}
+
+// interactionsHandler verifies Discord's Ed25519 signature, answers PINGs and passes every other
+// interaction to dispatch. Handlers respond through the interaction callback endpoint, so the HTTP
+// request is acknowledged with 202 right away instead of waiting for slow handlers past Discord's
+// 3 second window.
+func interactionsHandler(publicKey ed25519.PublicKey, dispatch func(*discordgo.InteractionCreate)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxInteractionBodySize)
+		if !discordgo.VerifyInteraction(r, publicKey) {
+			http.Error(w, "invalid request signature", http.StatusUnauthorized)
+			return
+		}
+
+		var interaction discordgo.Interaction
+		if err := json.NewDecoder(r.Body).Decode(&interaction); err != nil {
+			http.Error(w, "invalid interaction payload", http.StatusBadRequest)
+			return
+		}
+
+		if interaction.Type == discordgo.InteractionPing {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(discordgo.InteractionResponse{Type: discordgo.InteractionResponsePong})
+			return
+		}
+
+		go dispatch(&discordgo.InteractionCreate{Interaction: &interaction})
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
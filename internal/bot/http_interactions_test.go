@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// signedRequest builds an interaction request signed like Discord signs them.
+func signedRequest(t *testing.T, key ed25519.PrivateKey, body string) *http.Request {
+	t.Helper()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := ed25519.Sign(key, []byte(timestamp+body))
+
+	req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewBufferString(body))
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+	return req
+}
+
+func TestInteractionsHandler(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatched := make(chan *discordgo.InteractionCreate, 1)
+	handler := interactionsHandler(publicKey, func(i *discordgo.InteractionCreate) { dispatched <- i })
+
+	t.Run("bad signature", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, signedRequest(t, otherKey, `{"type":1}`))
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("ping", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, signedRequest(t, privateKey, `{"id":"1","type":1}`))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+		var resp discordgo.InteractionResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Type != discordgo.InteractionResponsePong {
+			t.Fatalf("response type = %d, want PONG", resp.Type)
+		}
+	})
+
+	t.Run("command", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, signedRequest(t, privateKey, `{"id":"2","type":2,"data":{"id":"3","name":"list","type":1}}`))
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusAccepted)
+		}
+		select {
+		case i := <-dispatched:
+			if name := i.ApplicationCommandData().Name; name != "list" {
+				t.Fatalf("dispatched command %q, want list", name)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("command was not dispatched")
+		}
+	})
+}
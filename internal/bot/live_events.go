@@ -0,0 +1,78 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// liveEventLeadTime is how far ahead a live event is scheduled; Discord rejects start times in the past.
+	liveEventLeadTime = 10 * time.Second
+	// liveEventDuration is the scheduled end of a live event, which external events require.
+	// The event is completed as soon as the stream actually ends.
+	liveEventDuration = 12 * time.Hour
+)
+
+// startLiveEvent creates an external scheduled event for a creator's stream and starts it right away,
+// if the guild mirrors live streams as events.
+func (b *Bot) startLiveEvent(user models.MonitoredUser) {
+	settings, err := b.Repo.GetGuildSettings(user.GuildID)
+	if err != nil || !settings.LiveEvents {
+		return
+	}
+	if user.LiveEventID != "" {
+		// The previous stream ended between two checks; close its event first.
+		b.endLiveEvent(user)
+	}
+
+	start := time.Now().Add(liveEventLeadTime)
+	end := start.Add(liveEventDuration)
+	event, err := b.Session.GuildScheduledEventCreate(user.GuildID, &discordgo.GuildScheduledEventParams{
+		Name:               embed.Truncate(fmt.Sprintf("%s is live on Fansly", user.Username), 100),
+		Description:        fmt.Sprintf("Watch %s live at https://fansly.com/live/%s", user.Username, user.Username),
+		ScheduledStartTime: &start,
+		ScheduledEndTime:   &end,
+		PrivacyLevel:       discordgo.GuildScheduledEventPrivacyLevelGuildOnly,
+		EntityType:         discordgo.GuildScheduledEventEntityTypeExternal,
+		EntityMetadata: &discordgo.GuildScheduledEventEntityMetadata{
+			Location: fmt.Sprintf("https://fansly.com/live/%s", user.Username),
+		},
+	})
+	if err != nil {
+		log.Printf("Error creating live event for %s in guild %s: %v", user.Username, user.GuildID, err)
+		return
+	}
+
+	if _, err := b.Session.GuildScheduledEventEdit(user.GuildID, event.ID, &discordgo.GuildScheduledEventParams{
+		Status: discordgo.GuildScheduledEventStatusActive,
+	}); err != nil {
+		log.Printf("Error starting live event for %s in guild %s: %v", user.Username, user.GuildID, err)
+	}
+	if err := b.Repo.UpdateLiveEventID(user.GuildID, user.UserID, event.ID); err != nil {
+		log.Printf("Error saving live event for %s in guild %s: %v", user.Username, user.GuildID, err)
+	}
+}
+
+// endLiveEvent completes the event of a creator's stream after it ended. The event is forgotten
+// even if completing fails, e.g. because it was deleted by hand.
+func (b *Bot) endLiveEvent(user models.MonitoredUser) {
+	b.completeLiveEvent(user)
+	if err := b.Repo.UpdateLiveEventID(user.GuildID, user.UserID, ""); err != nil {
+		log.Printf("Error clearing live event for %s in guild %s: %v", user.Username, user.GuildID, err)
+	}
+}
+
+// completeLiveEvent marks the creator's event as completed in Discord.
+func (b *Bot) completeLiveEvent(user models.MonitoredUser) {
+	_, err := b.Session.GuildScheduledEventEdit(user.GuildID, user.LiveEventID, &discordgo.GuildScheduledEventParams{
+		Status: discordgo.GuildScheduledEventStatusCompleted,
+	})
+	if err != nil {
+		log.Printf("Could not complete live event %s for %s in guild %s: %v", user.LiveEventID, user.Username, user.GuildID, err)
+	}
+}
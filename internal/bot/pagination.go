@@ -4,218 +4,362 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"strconv"
 	"strings"
-	"time"
 
+	"github.com/NotiFansly/notifansly-bot/internal/models"
 	"github.com/bwmarrin/discordgo"
 )
 
 const (
 	itemsPerPage = 5
+
+	// pageNamespace prefixes the custom IDs of pagination components. The list, page and
+	// filter are encoded in the custom ID and re-queried on every click, so the buttons keep
+	// working after a restart and on any instance.
+	pageNamespace = "page"
 )
 
-// sendPaginatedList now edits the deferred interaction response instead of creating a new one.
-func (b *Bot) sendPaginatedList(s *discordgo.Session, i *discordgo.InteractionCreate, items []string, initialPage int) {
-	totalPages := int(math.Ceil(float64(len(items)) / float64(itemsPerPage)))
+// List filters. Channel filters carry the channel ID after listFilterChannelPrefix.
+const (
+	listFilterAll           = "all"
+	listFilterPostsDisabled = "postsoff"
+	listFilterLiveOnly      = "liveonly"
+	listFilterLiveDisabled  = "liveoff"
+	listFilterChannelPrefix = "ch-"
+	auditTargetFilterPrefix = "t-"
+)
 
-	// Ensure initialPage is valid
-	if totalPages == 0 {
-		totalPages = 1 // Prevent division by zero or invalid page numbers if items is empty
-	}
-	if initialPage < 1 {
-		initialPage = 1
-	}
-	if initialPage > totalPages {
-		initialPage = totalPages
+// pagedList describes a list that can be browsed with pagination buttons.
+type pagedList struct {
+	title   string
+	empty   string
+	command string // the permission rules of this command apply to the buttons
+	filters bool   // whether to show the filter controls of /list
+	items   func(b *Bot, i *discordgo.InteractionCreate, filter string) ([]string, error)
+}
+
+var pagedLists = map[string]pagedList{
+	"list": {
+		title:   "Monitored Models",
+		empty:   "No models are being monitored.",
+		command: "list",
+		filters: true,
+		items: func(b *Bot, i *discordgo.InteractionCreate, filter string) ([]string, error) {
+			return b.monitoredUserItems(i.GuildID, filter)
+		},
+	},
+	"servers": {
+		title:   "Servers",
+		empty:   "The bot is not currently in any servers.",
+		command: "servers",
+		items: func(b *Bot, i *discordgo.InteractionCreate, filter string) ([]string, error) {
+			return b.serverItems(), nil
+		},
+	},
+	"auditlog": {
+		title:   "Audit Log",
+		empty:   "No configuration changes have been recorded yet.",
+		command: "auditlog",
+		items: func(b *Bot, i *discordgo.InteractionCreate, filter string) ([]string, error) {
+			return b.auditLogItems(i.GuildID, filter)
+		},
+	},
+}
+
+// matchesListFilter reports whether a creator is shown in /list with the given filter.
+func matchesListFilter(user models.MonitoredUser, filter string) bool {
+	switch {
+	case filter == listFilterPostsDisabled:
+		return !user.PostsEnabled
+	case filter == listFilterLiveOnly:
+		return !user.PostsEnabled && user.LiveEnabled
+	case filter == listFilterLiveDisabled:
+		return !user.LiveEnabled
+	case strings.HasPrefix(filter, listFilterChannelPrefix):
+		channelID := strings.TrimPrefix(filter, listFilterChannelPrefix)
+		return user.PostNotificationChannel == channelID || user.LiveNotificationChannel == channelID
+	default:
+		return true
 	}
+}
 
-	// Create initial embed and components
-	embed := createPageEmbed(items, initialPage, totalPages)
-	components := createPaginationComponents(initialPage, totalPages)
+// sendPagedList edits the deferred interaction response to show a page of the named list.
+func (b *Bot) sendPagedList(s *discordgo.Session, i *discordgo.InteractionCreate, listName, filter string, page int) {
+	data, err := b.renderPagedList(i, listName, filter, page)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching the list: %v", err))
+		return
+	}
 
-	// Instead of s.InteractionRespond, we use s.InteractionResponseEdit
-	// to update the "Thinking..." message that was sent by the deferral.
-	// Note that the fields in WebhookEdit are pointers.
-	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-		Embeds:     &[]*discordgo.MessageEmbed{embed},
-		Components: &components,
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &data.Embeds,
+		Components: &data.Components,
 	})
 	if err != nil {
 		log.Printf("Error editing interaction response for paginated list: %v", err)
-		return
+	}
+}
+
+// renderPagedList queries the list and renders the requested page with its controls.
+func (b *Bot) renderPagedList(i *discordgo.InteractionCreate, listName, filter string, page int) (*discordgo.InteractionResponseData, error) {
+	list, ok := pagedLists[listName]
+	if !ok {
+		return nil, fmt.Errorf("unknown list %q", listName)
 	}
 
-	// Get the message object from the interaction we just edited.
-	// This is necessary to get the Message ID for the button collector.
-	msg, err := s.InteractionResponse(i.Interaction)
+	items, err := list.items(b, i, filter)
 	if err != nil {
-		log.Printf("Error getting interaction response message after edit: %v", err)
-		return
+		return nil, err
 	}
 
-	// Set up a collector for button interactions on the message we just sent.
-	b.setupPaginationCollector(s, i.Member.User.ID, msg.ID, i.ChannelID, items, totalPages)
-}
+	totalPages := max(1, int(math.Ceil(float64(len(items))/float64(itemsPerPage))))
+	page = min(max(page, 1), totalPages)
 
-// createPageEmbed creates an embed for a specific page
-func createPageEmbed(items []string, page, totalPages int) *discordgo.MessageEmbed {
 	startIdx := (page - 1) * itemsPerPage
 	endIdx := min(startIdx+itemsPerPage, len(items))
 
-	var pageItems []string
+	description := list.empty
 	if startIdx < len(items) {
-		pageItems = items[startIdx:endIdx]
+		description = strings.Join(items[startIdx:endIdx], "\n\n")
 	}
 
-	description := "No models are being monitored."
-	if len(pageItems) > 0 {
-		description = strings.Join(pageItems, "\n\n")
+	footer := fmt.Sprintf("Page %d of %d", page, totalPages)
+	if label := filterLabel(filter); label != "" {
+		footer += " • Filter: " + label
 	}
 
-	return &discordgo.MessageEmbed{
-		Title:       "Monitored Models",
+	embed := &discordgo.MessageEmbed{
+		Title:       list.title,
 		Description: description,
 		Footer: &discordgo.MessageEmbedFooter{
-			Text: fmt.Sprintf("Page %d of %d", page, totalPages),
+			Text: footer,
 		},
 		Color: 0x03b2f8, // A nice blue color
 	}
+
+	var components []discordgo.MessageComponent
+	if totalPages > 1 {
+		components = append(components, createPaginationComponents(listName, filter, page, totalPages))
+	}
+	if list.filters {
+		components = append(components, createListFilterComponents(listName, filter)...)
+	}
+
+	return &discordgo.InteractionResponseData{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: components,
+	}, nil
+}
+
+// createPaginationComponents creates the navigation buttons for a page. Every button
+// carries the page it leads to.
+func createPaginationComponents(listName, filter string, currentPage, totalPages int) discordgo.MessageComponent {
+	navID := func(kind string, page int) string {
+		return makeCustomID(pageNamespace, "nav", listName, kind, strconv.Itoa(page), filter)
+	}
+
+	return discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "First",
+				Style:    discordgo.SecondaryButton,
+				CustomID: navID("first", 1),
+				Emoji:    &discordgo.ComponentEmoji{Name: "⏮️"},
+				Disabled: currentPage == 1,
+			},
+			discordgo.Button{
+				Label:    "Previous",
+				Style:    discordgo.PrimaryButton,
+				CustomID: navID("prev", max(1, currentPage-1)),
+				Emoji:    &discordgo.ComponentEmoji{Name: "⬅️"},
+				Disabled: currentPage == 1,
+			},
+			discordgo.Button{
+				Label:    "Next",
+				Style:    discordgo.PrimaryButton,
+				CustomID: navID("next", min(totalPages, currentPage+1)),
+				Emoji:    &discordgo.ComponentEmoji{Name: "➡️"},
+				Disabled: currentPage == totalPages,
+			},
+			discordgo.Button{
+				Label:    "Last",
+				Style:    discordgo.SecondaryButton,
+				CustomID: navID("last", totalPages),
+				Emoji:    &discordgo.ComponentEmoji{Name: "⏭️"},
+				Disabled: currentPage == totalPages,
+			},
+			discordgo.Button{
+				Label:    "Go to page",
+				Style:    discordgo.SecondaryButton,
+				CustomID: makeCustomID(pageNamespace, "jump", listName, filter),
+				Emoji:    &discordgo.ComponentEmoji{Name: "🔢"},
+			},
+		},
+	}
 }
 
-// createPaginationComponents creates the button components for pagination
-func createPaginationComponents(currentPage, totalPages int) []discordgo.MessageComponent {
-	// If there's only one page, no buttons are needed.
-	if totalPages <= 1 {
-		return nil
+// createListFilterComponents creates the filter select and channel select shown under /list.
+func createListFilterComponents(listName, filter string) []discordgo.MessageComponent {
+	option := func(label, value, description string) discordgo.SelectMenuOption {
+		return discordgo.SelectMenuOption{Label: label, Value: value, Description: description, Default: filter == value}
 	}
 
+	var channelDefault []discordgo.SelectMenuDefaultValue
+	if strings.HasPrefix(filter, listFilterChannelPrefix) {
+		channelDefault = selectDefault(strings.TrimPrefix(filter, listFilterChannelPrefix), discordgo.SelectMenuDefaultValueChannel)
+	}
+	noMinimum := 0
+
 	return []discordgo.MessageComponent{
 		discordgo.ActionsRow{
 			Components: []discordgo.MessageComponent{
-				discordgo.Button{
-					Label:    "First",
-					Style:    discordgo.SecondaryButton,
-					CustomID: "first_page",
-					Emoji:    &discordgo.ComponentEmoji{Name: "⏮️"},
-					Disabled: currentPage == 1,
-				},
-				discordgo.Button{
-					Label:    "Previous",
-					Style:    discordgo.PrimaryButton,
-					CustomID: "prev_page",
-					Emoji:    &discordgo.ComponentEmoji{Name: "⬅️"},
-					Disabled: currentPage == 1,
+				discordgo.SelectMenu{
+					CustomID:    makeCustomID(pageNamespace, "filter", listName),
+					Placeholder: "Filter creators",
+					Options: []discordgo.SelectMenuOption{
+						option("All creators", listFilterAll, "Show every monitored creator"),
+						option("Posts disabled", listFilterPostsDisabled, "Creators without post notifications"),
+						option("Live only", listFilterLiveOnly, "Creators announced only when they go live"),
+						option("Live disabled", listFilterLiveDisabled, "Creators without live notifications"),
+					},
 				},
-				discordgo.Button{
-					Label:    "Next",
-					Style:    discordgo.PrimaryButton,
-					CustomID: "next_page",
-					Emoji:    &discordgo.ComponentEmoji{Name: "➡️"},
-					Disabled: currentPage == totalPages,
-				},
-				discordgo.Button{
-					Label:    "Last",
-					Style:    discordgo.SecondaryButton,
-					CustomID: "last_page",
-					Emoji:    &discordgo.ComponentEmoji{Name: "⏭️"},
-					Disabled: currentPage == totalPages,
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:      discordgo.ChannelSelectMenu,
+					CustomID:      makeCustomID(pageNamespace, "channel", listName),
+					Placeholder:   "Filter by notification channel",
+					MinValues:     &noMinimum,
+					MaxValues:     1,
+					DefaultValues: channelDefault,
 				},
 			},
 		},
 	}
 }
 
-// setupPaginationCollector sets up a collector for pagination button interactions
-func (b *Bot) setupPaginationCollector(s *discordgo.Session, userID, messageID, channelID string, items []string, totalPages int) {
-	// Create a handler for button interactions
-	handlerFunc := s.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		if i.Type != discordgo.InteractionMessageComponent || i.Message.ID != messageID {
-			return
-		}
+func filterLabel(filter string) string {
+	switch {
+	case filter == listFilterPostsDisabled:
+		return "Posts disabled"
+	case filter == listFilterLiveOnly:
+		return "Live only"
+	case filter == listFilterLiveDisabled:
+		return "Live disabled"
+	case strings.HasPrefix(filter, listFilterChannelPrefix):
+		return "Channel"
+	case strings.HasPrefix(filter, auditTargetFilterPrefix):
+		return strings.TrimPrefix(filter, auditTargetFilterPrefix)
+	default:
+		return ""
+	}
+}
 
-		// Only allow the original command user to use the buttons
-		if i.Member.User.ID != userID {
-			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "Only the user who ran the command can use these buttons.",
-					Flags:   discordgo.MessageFlagsEphemeral,
-				},
-			})
+// handlePageComponent handles pagination buttons and the list filters.
+func (b *Bot) handlePageComponent(s *discordgo.Session, i *discordgo.InteractionCreate, action string, args []string) {
+	if len(args) == 0 || !b.authorizePagedList(s, i, args[0]) {
+		return
+	}
+	listName := args[0]
+
+	switch action {
+	case "nav":
+		if len(args) < 4 {
 			return
 		}
+		page, _ := strconv.Atoi(args[2])
+		b.updatePagedList(s, i, listName, args[3], page)
 
-		// Get the current page from the footer
-		var currentPage int
-		if len(i.Message.Embeds) > 0 && i.Message.Embeds[0].Footer != nil {
-			fmt.Sscanf(i.Message.Embeds[0].Footer.Text, "Page %d of %d", &currentPage, &totalPages)
+	case "filter":
+		filter := listFilterAll
+		if values := i.MessageComponentData().Values; len(values) > 0 {
+			filter = values[0]
 		}
+		b.updatePagedList(s, i, listName, filter, 1)
 
-		// Determine the new page based on the button pressed
-		newPage := currentPage
-		switch i.MessageComponentData().CustomID {
-		case "prev_page":
-			if currentPage > 1 {
-				newPage = currentPage - 1
-			}
-		case "next_page":
-			if currentPage < totalPages {
-				newPage = currentPage + 1
-			}
-		case "first_page":
-			newPage = 1
-		case "last_page":
-			newPage = totalPages
-		default:
-			// Acknowledge the interaction to prevent it from failing, but do nothing.
-			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-			return
+	case "channel":
+		filter := listFilterAll
+		if values := i.MessageComponentData().Values; len(values) > 0 {
+			filter = listFilterChannelPrefix + values[0]
 		}
+		b.updatePagedList(s, i, listName, filter, 1)
 
-		// If the page hasn't changed, do nothing.
-		if newPage == currentPage {
-			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
+	case "jump":
+		if len(args) < 2 {
 			return
 		}
-
-		// Create the new embed and components
-		embed := createPageEmbed(items, newPage, totalPages)
-		components := createPaginationComponents(newPage, totalPages)
-
-		// Update the message by responding to the button interaction
 		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseUpdateMessage,
+			Type: discordgo.InteractionResponseModal,
 			Data: &discordgo.InteractionResponseData{
-				Embeds:     []*discordgo.MessageEmbed{embed},
-				Components: components,
+				CustomID: makeCustomID(pageNamespace, "jump", listName, args[1]),
+				Title:    "Go to page",
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{
+						Components: []discordgo.MessageComponent{
+							discordgo.TextInput{
+								CustomID:  "page_number",
+								Label:     "Page number",
+								Style:     discordgo.TextInputShort,
+								Required:  true,
+								MaxLength: 5,
+							},
+						},
+					},
+				},
 			},
 		})
 		if err != nil {
-			log.Printf("Error updating paginated message: %v", err)
+			log.Printf("Error responding with modal: %v", err)
 		}
-	})
+	}
+}
 
-	// Remove the handler and buttons after 5 minutes of inactivity
-	time.AfterFunc(5*time.Minute, func() {
-		handlerFunc() // This invokes the returned function from AddHandler, which removes it.
+// handlePageModal handles the jump-to-page modal.
+func (b *Bot) handlePageModal(s *discordgo.Session, i *discordgo.InteractionCreate, action string, args []string) {
+	if action != "jump" || len(args) < 2 || !b.authorizePagedList(s, i, args[0]) {
+		return
+	}
 
-		// Get the final state of the embed
-		msg, err := s.ChannelMessage(channelID, messageID)
-		if err != nil {
-			// Message might have been deleted, which is fine.
-			return
+	page, err := strconv.Atoi(strings.TrimSpace(modalValues(i.ModalSubmitData())["page_number"]))
+	if err != nil {
+		b.respondToInteraction(s, i, "Please enter a valid page number.", true)
+		return
+	}
+	b.updatePagedList(s, i, args[0], args[1], page)
+}
+
+func (b *Bot) authorizePagedList(s *discordgo.Session, i *discordgo.InteractionCreate, listName string) bool {
+	list, ok := pagedLists[listName]
+	if !ok {
+		return false
+	}
+	if ownerOnlyCommands[list.command] {
+		if !b.isBotOwner(i) {
+			b.respondToInteraction(s, i, "This command is for the bot owner only.", true)
+			return false
 		}
+		return true
+	}
+	return b.authorizeComponent(s, i, list.command)
+}
 
-		// Edit the message to remove the buttons.
-		_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
-			Channel:    channelID,
-			ID:         messageID,
-			Embeds:     &msg.Embeds,                     // Keep the existing embeds
-			Components: &[]discordgo.MessageComponent{}, // Set components to an empty slice
-		})
+// updatePagedList re-renders a list in the message the interaction came from.
+func (b *Bot) updatePagedList(s *discordgo.Session, i *discordgo.InteractionCreate, listName, filter string, page int) {
+	data, err := b.renderPagedList(i, listName, filter, page)
+	if err != nil {
+		log.Printf("Error rendering list %s: %v", listName, err)
+		b.respondToInteraction(s, i, "An error occurred while fetching the list.", true)
+		return
+	}
 
-		if err != nil {
-			log.Printf("Error removing pagination buttons after timeout: %v", err)
-		}
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: data,
 	})
+	if err != nil {
+		log.Printf("Error updating paginated message: %v", err)
+	}
 }
@@ -0,0 +1,147 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/api"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	addNamespace = "add"
+
+	// pendingAddTTL is how long a live-only confirmation can be answered.
+	pendingAddTTL = 15 * time.Minute
+)
+
+// requestLiveOnlyConfirmation stores the pending add and asks the requester, in an ephemeral
+// message, whether to add the creator for live notifications only.
+func (b *Bot) requestLiveOnlyConfirmation(s *discordgo.Session, i *discordgo.InteractionCreate, pending *models.PendingAdd) {
+	if err := b.Repo.CreatePendingAdd(pending); err != nil {
+		log.Printf("Error storing pending add for %s: %v", pending.Username, err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Cannot access timeline for **%s**, and the confirmation could not be saved: %v", pending.Username, err))
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("Cannot access timeline for **%s**. Please confirm how to continue.", pending.Username))
+
+	_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: fmt.Sprintf("Do you want to add **%s** for **live notifications only**? This confirmation expires <t:%d:R>.", pending.Username, pending.ExpiresAt),
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "Add for live only",
+						Style:    discordgo.SuccessButton,
+						CustomID: makeCustomID(addNamespace, "confirm", pending.ID),
+						Emoji:    &discordgo.ComponentEmoji{Name: "✅"},
+					},
+					discordgo.Button{
+						Label:    "Cancel",
+						Style:    discordgo.DangerButton,
+						CustomID: makeCustomID(addNamespace, "cancel", pending.ID),
+						Emoji:    &discordgo.ComponentEmoji{Name: "❌"},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Error sending confirmation message: %v", err)
+	}
+}
+
+// handleAddComponent handles the buttons of a live-only confirmation.
+func (b *Bot) handleAddComponent(s *discordgo.Session, i *discordgo.InteractionCreate, action string, args []string) {
+	if len(args) == 0 {
+		return
+	}
+
+	pending, err := b.Repo.GetPendingAdd(args[0])
+	if err != nil {
+		log.Printf("Error fetching pending add %s: %v", args[0], err)
+		b.respondToInteraction(s, i, "An error occurred while loading this confirmation.", true)
+		return
+	}
+	if pending == nil {
+		b.updateConfirmation(s, i, "This confirmation has expired. Please run `/add` again.")
+		return
+	}
+	if i.Member == nil || i.Member.User.ID != pending.RequesterID {
+		b.respondToInteraction(s, i, "Only the user who ran the command can use these buttons.", true)
+		return
+	}
+
+	// Whoever deletes the pending add first acts on it, so double clicks and other
+	// instances don't add the creator twice.
+	deleted, err := b.Repo.DeletePendingAdd(pending.ID)
+	if err != nil {
+		log.Printf("Error deleting pending add %s: %v", pending.ID, err)
+		b.respondToInteraction(s, i, "An error occurred while processing this confirmation.", true)
+		return
+	}
+	if !deleted {
+		b.updateConfirmation(s, i, "This confirmation was already answered.")
+		return
+	}
+
+	switch action {
+	case "confirm":
+		// Other creators may have been added while the confirmation was open, so the
+		// server's limit is checked again.
+		if guildLimit := b.guildUserLimit(pending.GuildID); guildLimit > 0 {
+			existing, err := b.Repo.GetMonitoredUser(pending.GuildID, pending.UserID)
+			if err != nil {
+				log.Printf("Error checking existing entry for %s in guild %s: %v", pending.Username, pending.GuildID, err)
+				b.updateConfirmation(s, i, "An error occurred while checking the server's limit. Please try again later.")
+				return
+			}
+			if existing == nil {
+				count, err := b.Repo.CountMonitoredUsersForGuild(pending.GuildID)
+				if err != nil {
+					log.Printf("Error checking guild limit for guild %s: %v", pending.GuildID, err)
+					b.updateConfirmation(s, i, "An error occurred while checking the server's limit. Please try again later.")
+					return
+				}
+				if count >= int64(guildLimit) {
+					b.updateConfirmation(s, i, guildLimitMessage(pending.GuildID, guildLimit))
+					return
+				}
+			}
+		}
+
+		account := &api.ModelAccountInfo{ID: pending.UserID, Username: pending.Username, DisplayName: pending.DisplayName}
+		user := newMonitoredUser(pending.GuildID, account, pending.Username, pending.ChannelID, pending.MentionRole, false)
+		user.AvatarLocation = pending.AvatarLocation
+
+		if err := b.Repo.AddOrUpdateMonitoredUser(user); err != nil {
+			b.updateConfirmation(s, i, fmt.Sprintf("Error adding user: %v", err))
+			return
+		}
+		b.accounts.add(pending.UserID, pending.Username, pending.DisplayName)
+		b.updateConfirmation(s, i, fmt.Sprintf("✅ Added **%s** for live notifications only.", pending.Username))
+		b.recordAudit(s, i, pending.Username, pending.PreviousSettings, fmt.Sprintf("Live only in %s", channelText(pending.ChannelID)))
+		go b.prepareDeliveryThreads(pending.GuildID, pending.UserID)
+
+	default:
+		b.updateConfirmation(s, i, "❌ Operation cancelled.")
+	}
+}
+
+// updateConfirmation replaces the confirmation message with content and removes its buttons.
+func (b *Bot) updateConfirmation(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+	if err != nil {
+		log.Printf("Error updating confirmation message: %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// canRunCommand reports whether the invoking member may run command in this guild.
+// Administrators, members with Manage Server and the guild owner can run every command.
+// Otherwise, if the guild configured role overrides for the command, one of those roles
+// is required; if not, one of the guild's manager roles is.
+func (b *Bot) canRunCommand(s *discordgo.Session, i *discordgo.InteractionCreate, command string) bool {
+	if b.hasAdminOrModPermissions(s, i) {
+		return true
+	}
+	if i.GuildID == "" || i.Member == nil || command == "permissions" {
+		return false
+	}
+
+	allowedRoles, err := b.Repo.GetCommandRoles(i.GuildID, command)
+	if err != nil {
+		log.Printf("Error fetching command permissions for guild %s: %v", i.GuildID, err)
+		return false
+	}
+	if len(allowedRoles) == 0 {
+		allowedRoles, err = b.Repo.GetManagerRoles(i.GuildID)
+		if err != nil {
+			log.Printf("Error fetching manager roles for guild %s: %v", i.GuildID, err)
+			return false
+		}
+	}
+
+	for _, roleID := range allowedRoles {
+		// The @everyone role shares the guild's ID and is never listed in member roles.
+		if roleID == i.GuildID || slices.Contains(i.Member.Roles, roleID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bot) handlePermissionsCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	subcommand := i.ApplicationCommandData().Options[0]
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range subcommand.Options {
+		options[opt.Name] = opt
+	}
+
+	var roleID, roleMention string
+	if opt, ok := options["role"]; ok {
+		roleID = opt.Value.(string)
+		roleMention = getRoleName(roleID)
+		if roleID == i.GuildID {
+			roleMention = "@everyone"
+		}
+	}
+
+	switch subcommand.Name {
+	case "view":
+		b.editInteractionResponse(s, i, b.describePermissions(i.GuildID))
+
+	case "addmanager":
+		if err := b.Repo.AddManagerRole(i.GuildID, roleID); err != nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error adding manager role: %v", err))
+			return
+		}
+		b.editInteractionResponse(s, i, fmt.Sprintf("✅ Members with %s can now use all management commands.", roleMention))
+		b.recordAudit(s, i, "manager roles", "", roleMention)
+
+	case "removemanager":
+		if err := b.Repo.RemoveManagerRole(i.GuildID, roleID); err != nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error removing manager role: %v", err))
+			return
+		}
+		b.editInteractionResponse(s, i, fmt.Sprintf("%s is no longer a manager role.", roleMention))
+		b.recordAudit(s, i, "manager roles", roleMention, "")
+
+	case "allow":
+		command := strings.TrimPrefix(options["command"].StringValue(), "/")
+		if !slices.Contains(delegableCommands(), command) {
+			b.editInteractionResponse(s, i, fmt.Sprintf("`/%s` is not a command that can be delegated to roles.", command))
+			return
+		}
+		if err := b.Repo.AddCommandPermission(i.GuildID, command, roleID); err != nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error saving permission: %v", err))
+			return
+		}
+		b.editInteractionResponse(s, i, fmt.Sprintf("✅ Members with %s can now use `/%s`. Manager roles no longer apply to this command unless allowed explicitly.", roleMention, command))
+		b.recordAudit(s, i, "/"+command, "", roleMention)
+
+	case "reset":
+		command := strings.TrimPrefix(options["command"].StringValue(), "/")
+		if err := b.Repo.RemoveCommandPermissions(i.GuildID, command, roleID); err != nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error removing permission: %v", err))
+			return
+		}
+		if roleID != "" {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Removed the `/%s` override for %s.", command, roleMention))
+			b.recordAudit(s, i, "/"+command, roleMention, "")
+		} else {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Removed all overrides for `/%s`; manager roles apply again.", command))
+			b.recordAudit(s, i, "/"+command, "all overrides", "")
+		}
+	}
+}
+
+func (b *Bot) describePermissions(guildID string) string {
+	managerRoles, err := b.Repo.GetManagerRoles(guildID)
+	if err != nil {
+		return fmt.Sprintf("Error fetching manager roles: %v", err)
+	}
+	overrides, err := b.Repo.GetCommandPermissions(guildID)
+	if err != nil {
+		return fmt.Sprintf("Error fetching command overrides: %v", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("**Manager roles:** ")
+	if len(managerRoles) == 0 {
+		sb.WriteString("None (only administrators and members with Manage Server)")
+	} else {
+		mentions := make([]string, len(managerRoles))
+		for idx, roleID := range managerRoles {
+			mentions[idx] = getRoleName(roleID)
+		}
+		sb.WriteString(strings.Join(mentions, ", "))
+	}
+
+	sb.WriteString("\n\n**Command overrides:**")
+	if len(overrides) == 0 {
+		sb.WriteString(" None")
+	}
+	byCommand := make(map[string][]string)
+	var commands []string
+	for _, o := range overrides {
+		if _, ok := byCommand[o.Command]; !ok {
+			commands = append(commands, o.Command)
+		}
+		mention := getRoleName(o.RoleID)
+		if o.RoleID == guildID {
+			mention = "@everyone"
+		}
+		byCommand[o.Command] = append(byCommand[o.Command], mention)
+	}
+	for _, command := range commands {
+		sb.WriteString(fmt.Sprintf("\n- `/%s`: %s", command, strings.Join(byCommand[command], ", ")))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/NotiFansly/notifansly-bot/internal/database"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCanRunCommand(t *testing.T) {
+	const (
+		guildID     = "guild"
+		ownerID     = "owner"
+		managerRole = "manager"
+		editorRole  = "editor"
+	)
+
+	tests := []struct {
+		name        string
+		dm          bool
+		userID      string
+		permissions int64
+		roles       []string
+		managers    []string
+		overrides   map[string][]string // command -> roles
+		command     string
+		want        bool
+	}{
+		{name: "administrator", permissions: discordgo.PermissionAdministrator, command: "add", want: true},
+		{name: "manage server", permissions: discordgo.PermissionManageGuild, command: "permissions", want: true},
+		{name: "guild owner", userID: ownerID, command: "add", want: true},
+		{name: "no manager roles", roles: []string{managerRole}, command: "add", want: false},
+		{name: "manager role", roles: []string{managerRole}, managers: []string{managerRole}, command: "add", want: true},
+		{name: "member without a manager role", roles: []string{editorRole}, managers: []string{managerRole}, command: "add", want: false},
+		{name: "managers cannot manage permissions", roles: []string{managerRole}, managers: []string{managerRole}, command: "permissions", want: false},
+		{
+			name:      "override replaces the manager roles",
+			roles:     []string{managerRole},
+			managers:  []string{managerRole},
+			overrides: map[string][]string{"remove": {editorRole}},
+			command:   "remove",
+			want:      false,
+		},
+		{
+			name:      "override role",
+			roles:     []string{editorRole},
+			managers:  []string{managerRole},
+			overrides: map[string][]string{"remove": {editorRole}},
+			command:   "remove",
+			want:      true,
+		},
+		{
+			name:      "override of another command",
+			roles:     []string{editorRole},
+			overrides: map[string][]string{"remove": {editorRole}},
+			command:   "add",
+			want:      false,
+		},
+		{
+			name:      "override for everyone",
+			overrides: map[string][]string{"list": {guildID}},
+			command:   "list",
+			want:      true,
+		},
+		{name: "direct message", dm: true, command: "add", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := database.Init("sqlite", filepath.Join(t.TempDir(), "test.db")); err != nil {
+				t.Fatalf("could not initialize the database: %v", err)
+			}
+			t.Cleanup(database.Close)
+			b := &Bot{Repo: database.NewRepository()}
+
+			for _, roleID := range tt.managers {
+				if err := b.Repo.AddManagerRole(guildID, roleID); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for command, roles := range tt.overrides {
+				for _, roleID := range roles {
+					if err := b.Repo.AddCommandPermission(guildID, command, roleID); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			state := discordgo.NewState()
+			if err := state.GuildAdd(&discordgo.Guild{ID: guildID, OwnerID: ownerID}); err != nil {
+				t.Fatal(err)
+			}
+			s := &discordgo.Session{State: state}
+
+			interactionGuild := guildID
+			if tt.dm {
+				interactionGuild = ""
+			}
+			userID := tt.userID
+			if userID == "" {
+				userID = "member"
+			}
+			i := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+				GuildID: interactionGuild,
+				Member: &discordgo.Member{
+					User:        &discordgo.User{ID: userID},
+					Roles:       tt.roles,
+					Permissions: tt.permissions,
+				},
+			}}
+
+			if got := b.canRunCommand(s, i, tt.command); got != tt.want {
+				t.Errorf("canRunCommand(%q) = %v, want %v", tt.command, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// publishesPerHour is Discord's limit on publishing messages in one announcement channel.
+	publishesPerHour = 10
+	// publishRetryInterval is how often queued messages are retried while a channel is rate limited.
+	publishRetryInterval = time.Minute
+	// publishMaxAge drops messages that could not be published for this long; by then the
+	// notification is old news for following servers.
+	publishMaxAge = 3 * time.Hour
+)
+
+type publishJob struct {
+	channelID string
+	messageID string
+	queuedAt  time.Time
+}
+
+// publisher crossposts notifications from announcement channels without exceeding Discord's
+// per-channel publish limit. Messages over the limit wait in the queue instead of blocking workers.
+type publisher struct {
+	mu           sync.Mutex
+	queue        []publishJob
+	published    map[string][]time.Time // publish times per channel within the last hour
+	blockedUntil map[string]time.Time   // channels Discord rate limited
+	denied       map[string]bool        // channels the bot lacked permission to publish in
+	wake         chan struct{}
+}
+
+func newPublisher() *publisher {
+	return &publisher{
+		published:    make(map[string][]time.Time),
+		blockedUntil: make(map[string]time.Time),
+		denied:       make(map[string]bool),
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+func (p *publisher) enqueue(channelID, messageID string) {
+	p.mu.Lock()
+	p.queue = append(p.queue, publishJob{channelID: channelID, messageID: messageID, queuedAt: time.Now()})
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// canPublish reports whether a channel has publish budget left. Only the publisher loop calls it.
+func (p *publisher) canPublish(channelID string, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if now.Before(p.blockedUntil[channelID]) {
+		return false
+	}
+	recent := p.published[channelID][:0]
+	for _, t := range p.published[channelID] {
+		if now.Sub(t) < time.Hour {
+			recent = append(recent, t)
+		}
+	}
+	p.published[channelID] = recent
+	return len(recent) < publishesPerHour
+}
+
+// publishDenied reports whether the last publish attempt in a channel failed for missing permissions.
+func (p *publisher) publishDenied(channelID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.denied[channelID]
+}
+
+// publishIfAnnouncement queues msg for crossposting if the guild enabled auto-publish and msg was
+// sent to an announcement channel.
+func (b *Bot) publishIfAnnouncement(settings *models.GuildSettings, msg *discordgo.Message) {
+	if !settings.AutoPublish || msg == nil {
+		return
+	}
+	if channel, err := b.channel(msg.ChannelID); err != nil || channel.Type != discordgo.ChannelTypeGuildNews {
+		return
+	}
+	b.publisher.enqueue(msg.ChannelID, msg.ID)
+}
+
+func (b *Bot) publishQueuedMessages() {
+	ticker := time.NewTicker(publishRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		b.publishPending()
+
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-b.publisher.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishPending crossposts every queued message whose channel has budget left and keeps the rest queued.
+func (b *Bot) publishPending() {
+	p := b.publisher
+	p.mu.Lock()
+	pending := p.queue
+	p.queue = nil
+	p.mu.Unlock()
+
+	var kept []publishJob
+	for _, job := range pending {
+		now := time.Now()
+		if now.Sub(job.queuedAt) > publishMaxAge {
+			log.Printf("[Publish] Dropping message %s in channel %s after waiting %s", job.messageID, job.channelID, publishMaxAge)
+			continue
+		}
+		if !p.canPublish(job.channelID, now) {
+			kept = append(kept, job)
+			continue
+		}
+
+		_, err := b.Session.ChannelMessageCrosspost(job.channelID, job.messageID, discordgo.WithRetryOnRatelimit(false))
+
+		var rateLimitErr *discordgo.RateLimitError
+		var restErr *discordgo.RESTError
+		p.mu.Lock()
+		switch {
+		case err == nil:
+			p.published[job.channelID] = append(p.published[job.channelID], now)
+			delete(p.denied, job.channelID)
+		case errors.As(err, &rateLimitErr):
+			p.blockedUntil[job.channelID] = now.Add(rateLimitErr.RetryAfter)
+			kept = append(kept, job)
+		case errors.As(err, &restErr) && restErr.Message != nil &&
+			(restErr.Message.Code == discordgo.ErrCodeMissingPermissions || restErr.Message.Code == discordgo.ErrCodeMissingAccess):
+			p.denied[job.channelID] = true
+			log.Printf("[Publish] Missing permission to publish in channel %s", job.channelID)
+		default:
+			log.Printf("[Publish] Error publishing message %s in channel %s: %v", job.messageID, job.channelID, err)
+		}
+		p.mu.Unlock()
+	}
+
+	p.mu.Lock()
+	p.queue = append(kept, p.queue...)
+	p.mu.Unlock()
+}
+
+// publishWarning returns a note for /list if notifications in channelID should be published but can't be.
+func (b *Bot) publishWarning(settings *models.GuildSettings, channelID string) string {
+	if !settings.AutoPublish || channelID == "" {
+		return ""
+	}
+	channel, err := b.channel(channelID)
+	if err != nil || channel.Type != discordgo.ChannelTypeGuildNews {
+		return ""
+	}
+
+	const required = discordgo.PermissionViewChannel | discordgo.PermissionSendMessages
+	perms, err := b.Session.State.UserChannelPermissions(b.Session.State.User.ID, channelID)
+	if (err == nil && perms&required != required) || b.publisher.publishDenied(channelID) {
+		return " ⚠️ *missing permission to publish*"
+	}
+	return ""
+}
@@ -0,0 +1,302 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata" // Timezones must resolve even where the host has no zoneinfo.
+
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	quietModeMute = "mute"
+	quietModeHold = "hold"
+
+	// maxEmbedsPerMessage is Discord's limit on embeds in a single message.
+	maxEmbedsPerMessage = 10
+	// heldReleaseInterval is how often held notifications are checked for release.
+	heldReleaseInterval = time.Minute
+	// heldMaxAge drops held notifications that still could not be delivered after this long.
+	heldMaxAge = 48 * time.Hour
+)
+
+// guildLocation returns the guild's configured timezone, falling back to UTC.
+func guildLocation(settings *models.GuildSettings) *time.Location {
+	if settings.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(value string) (int, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// inQuietHours reports whether now falls inside the guild's quiet hours. The window may wrap past midnight.
+func inQuietHours(settings *models.GuildSettings, now time.Time) bool {
+	start, okStart := parseClock(settings.QuietHoursStart)
+	end, okEnd := parseClock(settings.QuietHoursEnd)
+	if !okStart || !okEnd || start == end {
+		return false
+	}
+
+	local := now.In(guildLocation(settings))
+	minute := local.Hour()*60 + local.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// describeQuietHours summarizes a guild's quiet hours, for responses and audit entries.
+func describeQuietHours(settings *models.GuildSettings) string {
+	if settings.QuietHoursStart == "" || settings.QuietHoursEnd == "" {
+		return "disabled"
+	}
+	mode := "mentions muted"
+	if settings.QuietHoursMode == quietModeHold {
+		mode = "notifications held"
+	}
+	return fmt.Sprintf("%s–%s %s, %s", settings.QuietHoursStart, settings.QuietHoursEnd, guildLocation(settings), mode)
+}
+
+// sendGuildNotification posts a creator notification to a guild channel, honouring the guild's
+// quiet hours: mentions are suppressed, or the notification is held until quiet hours end.
+// The returned message is nil if the notification was held, and mentioned reports whether the
+// sent message was allowed to ping.
+func (b *Bot) sendGuildNotification(guildID, creatorID, channelID, content string, embedMsg *discordgo.MessageEmbed, suppressMentions bool) (sent *discordgo.Message, mentioned bool, err error) {
+	settings, err := b.Repo.GetGuildSettings(guildID)
+	if err != nil {
+		log.Printf("Could not fetch settings for guild %s, ignoring quiet hours: %v", guildID, err)
+		settings = &models.GuildSettings{GuildID: guildID}
+	}
+
+	msg := &discordgo.MessageSend{
+		Content: content,
+		Embed:   embedMsg,
+	}
+	if inQuietHours(settings, time.Now()) {
+		if settings.QuietHoursMode == quietModeHold {
+			return nil, false, b.holdNotification(guildID, creatorID, channelID, content, embedMsg, suppressMentions)
+		}
+		suppressMentions = true
+	}
+	if suppressMentions {
+		msg.AllowedMentions = &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}}
+	}
+
+	sent, err = b.Session.ChannelMessageSendComplex(channelID, msg)
+	if err != nil {
+		return nil, false, err
+	}
+	b.publishIfAnnouncement(settings, sent)
+	return sent, !suppressMentions, nil
+}
+
+func (b *Bot) holdNotification(guildID, creatorID, channelID, content string, embedMsg *discordgo.MessageEmbed, suppressMentions bool) error {
+	embedJSON, err := json.Marshal(embedMsg)
+	if err != nil {
+		return err
+	}
+	return b.Repo.AddHeldNotification(&models.HeldNotification{
+		GuildID:          guildID,
+		CreatorID:        creatorID,
+		ChannelID:        channelID,
+		Content:          content,
+		EmbedJSON:        string(embedJSON),
+		SuppressMentions: suppressMentions,
+		CreatedAt:        time.Now().Unix(),
+	})
+}
+
+func (b *Bot) releaseHeldNotificationsPeriodically() {
+	ticker := time.NewTicker(heldReleaseInterval)
+	defer ticker.Stop()
+
+	for {
+		b.releaseHeldNotifications()
+
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// releaseHeldNotifications delivers the held notifications of every guild whose quiet hours are over.
+func (b *Bot) releaseHeldNotifications() {
+	guildIDs, err := b.Repo.GetGuildsWithHeldNotifications()
+	if err != nil {
+		log.Printf("[QuietHours] Error fetching guilds with held notifications: %v", err)
+		return
+	}
+
+	for _, guildID := range guildIDs {
+		settings, err := b.Repo.GetGuildSettings(guildID)
+		if err != nil {
+			log.Printf("[QuietHours] Could not fetch settings for guild %s: %v", guildID, err)
+			continue
+		}
+		if inQuietHours(settings, time.Now()) {
+			continue
+		}
+
+		held, err := b.Repo.GetHeldNotifications(guildID)
+		if err != nil {
+			log.Printf("[QuietHours] Error fetching held notifications for guild %s: %v", guildID, err)
+			continue
+		}
+		b.deliverHeldNotifications(settings, held)
+	}
+}
+
+// deliverHeldNotifications sends held notifications as a batch per channel, several embeds per message.
+// Each role or user is pinged at most once per release, and never for notifications held while the
+// creator's mention cooldown was active. Delivered notifications are removed; the rest are retried
+// until they are older than heldMaxAge.
+func (b *Bot) deliverHeldNotifications(settings *models.GuildSettings, held []models.HeldNotification) {
+	guildID := settings.GuildID
+	var channelOrder []string
+	byChannel := make(map[string][]models.HeldNotification)
+	for _, notification := range held {
+		if _, ok := byChannel[notification.ChannelID]; !ok {
+			channelOrder = append(channelOrder, notification.ChannelID)
+		}
+		byChannel[notification.ChannelID] = append(byChannel[notification.ChannelID], notification)
+	}
+
+	var doneIDs []uint
+	pinged := make(map[string]bool)
+	mentionedCreators := make(map[string]bool)
+	expired := time.Now().Add(-heldMaxAge).Unix()
+	for _, channelID := range channelOrder {
+		notifications := byChannel[channelID]
+		batches, unreadable := batchHeldNotifications(notifications)
+		doneIDs = append(doneIDs, unreadable...)
+		for idx, batch := range batches {
+			var contents []string
+			var embeds []*discordgo.MessageEmbed
+			allowed := &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}}
+			for _, notification := range batch {
+				if notification.Content != "" && !slices.Contains(contents, notification.Content) {
+					contents = append(contents, notification.Content)
+				}
+				embeds = append(embeds, notification.embed)
+				if !notification.SuppressMentions {
+					allowMentions(allowed, notification.Content, pinged)
+				}
+			}
+
+			b.reopenThreadByID(channelID)
+			content := strings.Join(contents, "\n")
+			if idx == 0 {
+				content = fmt.Sprintf("🌅 **Quiet hours are over.** %d notification(s) arrived in the meantime.\n%s", len(notifications), content)
+			}
+			sent, err := b.Session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+				Content:         embed.Truncate(content, 2000),
+				Embeds:          embeds,
+				AllowedMentions: allowed,
+			})
+			for _, notification := range batch {
+				if err == nil || isUnknownChannel(err) || notification.CreatedAt < expired {
+					doneIDs = append(doneIDs, notification.ID)
+				}
+			}
+			if err != nil {
+				log.Printf("[QuietHours] Error releasing held notifications to channel %s in guild %s: %v", channelID, guildID, err)
+				continue
+			}
+			for _, id := range allowed.Roles {
+				pinged["role:"+id] = true
+			}
+			for _, id := range allowed.Users {
+				pinged["user:"+id] = true
+			}
+			for _, notification := range batch {
+				if !notification.SuppressMentions && notification.Content != "" && notification.CreatorID != "" {
+					mentionedCreators[notification.CreatorID] = true
+				}
+			}
+			b.publishIfAnnouncement(settings, sent)
+		}
+	}
+
+	// The release pinged on behalf of these creators, so their mention cooldowns start now.
+	now := time.Now().Unix()
+	for creatorID := range mentionedCreators {
+		if err := b.Repo.UpdateLastMentionAt(guildID, creatorID, now); err != nil {
+			log.Printf("[QuietHours] Error updating last mention time for %s in guild %s: %v", creatorID, guildID, err)
+		}
+	}
+
+	if err := b.Repo.DeleteHeldNotifications(doneIDs); err != nil {
+		log.Printf("[QuietHours] Error deleting released notifications for guild %s: %v", guildID, err)
+	}
+}
+
+// mentionPattern matches user (<@id>, <@!id>) and role (<@&id>) mentions.
+var mentionPattern = regexp.MustCompile(`<@([!&]?)(\d+)>`)
+
+// allowMentions adds the users and roles mentioned in content to allowed, skipping any that were
+// already pinged or allowed.
+func allowMentions(allowed *discordgo.MessageAllowedMentions, content string, pinged map[string]bool) {
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		if match[1] == "&" {
+			if !pinged["role:"+match[2]] && !slices.Contains(allowed.Roles, match[2]) {
+				allowed.Roles = append(allowed.Roles, match[2])
+			}
+		} else if !pinged["user:"+match[2]] && !slices.Contains(allowed.Users, match[2]) {
+			allowed.Users = append(allowed.Users, match[2])
+		}
+	}
+}
+
+// heldBatchItem is a held notification with its decoded embed.
+type heldBatchItem struct {
+	models.HeldNotification
+	embed *discordgo.MessageEmbed
+}
+
+// batchHeldNotifications groups held notifications into messages that stay within Discord's limits
+// on embeds per message and on their combined length. It also returns the IDs of unreadable
+// notifications, which can never be sent.
+func batchHeldNotifications(notifications []models.HeldNotification) (batches [][]heldBatchItem, unreadable []uint) {
+	var batch []heldBatchItem
+	var length int
+	for _, notification := range notifications {
+		var embedMsg discordgo.MessageEmbed
+		if err := json.Unmarshal([]byte(notification.EmbedJSON), &embedMsg); err != nil {
+			log.Printf("[QuietHours] Dropping unreadable held notification %d: %v", notification.ID, err)
+			unreadable = append(unreadable, notification.ID)
+			continue
+		}
+		size := embed.Length(&embedMsg)
+		if len(batch) == maxEmbedsPerMessage || (len(batch) > 0 && length+size > embed.MaxMessageEmbedLength) {
+			batches = append(batches, batch)
+			batch, length = nil, 0
+		}
+		batch = append(batch, heldBatchItem{HeldNotification: notification, embed: &embedMsg})
+		length += size
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches, unreadable
+}
@@ -0,0 +1,164 @@
+package bot
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestGuildLocation(t *testing.T) {
+	tests := []struct {
+		timezone string
+		want     string
+	}{
+		{timezone: "", want: "UTC"},
+		{timezone: "Europe/Berlin", want: "Europe/Berlin"},
+		{timezone: "Not/A_Zone", want: "UTC"},
+		{timezone: "../etc/passwd", want: "UTC"},
+	}
+
+	for _, tt := range tests {
+		got := guildLocation(&models.GuildSettings{Timezone: tt.timezone})
+		if got.String() != tt.want {
+			t.Errorf("guildLocation(%q) = %s, want %s", tt.timezone, got, tt.want)
+		}
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Berlin"); err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	utc := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		settings models.GuildSettings
+		now      time.Time
+		want     bool
+	}{
+		{
+			name:     "disabled",
+			settings: models.GuildSettings{},
+			now:      utc(2025, 6, 1, 3, 0),
+		},
+		{
+			name:     "invalid start",
+			settings: models.GuildSettings{QuietHoursStart: "25:00", QuietHoursEnd: "07:00"},
+			now:      utc(2025, 6, 1, 3, 0),
+		},
+		{
+			name:     "empty window",
+			settings: models.GuildSettings{QuietHoursStart: "07:00", QuietHoursEnd: "07:00"},
+			now:      utc(2025, 6, 1, 7, 0),
+		},
+		{
+			name:     "inside same day window",
+			settings: models.GuildSettings{QuietHoursStart: "13:00", QuietHoursEnd: "15:00"},
+			now:      utc(2025, 6, 1, 14, 59),
+			want:     true,
+		},
+		{
+			name:     "end of same day window is exclusive",
+			settings: models.GuildSettings{QuietHoursStart: "13:00", QuietHoursEnd: "15:00"},
+			now:      utc(2025, 6, 1, 15, 0),
+		},
+		{
+			name:     "before midnight in window across midnight",
+			settings: models.GuildSettings{QuietHoursStart: "22:00", QuietHoursEnd: "07:00"},
+			now:      utc(2025, 6, 1, 23, 30),
+			want:     true,
+		},
+		{
+			name:     "after midnight in window across midnight",
+			settings: models.GuildSettings{QuietHoursStart: "22:00", QuietHoursEnd: "07:00"},
+			now:      utc(2025, 6, 2, 6, 59),
+			want:     true,
+		},
+		{
+			name:     "outside window across midnight",
+			settings: models.GuildSettings{QuietHoursStart: "22:00", QuietHoursEnd: "07:00"},
+			now:      utc(2025, 6, 1, 12, 0),
+		},
+		{
+			name:     "start of window across midnight is inclusive",
+			settings: models.GuildSettings{QuietHoursStart: "22:00", QuietHoursEnd: "07:00"},
+			now:      utc(2025, 6, 1, 22, 0),
+			want:     true,
+		},
+		{
+			name:     "guild timezone in winter",
+			settings: models.GuildSettings{Timezone: "Europe/Berlin", QuietHoursStart: "22:00", QuietHoursEnd: "07:00"},
+			now:      utc(2025, 3, 29, 5, 30), // 06:30 CET
+			want:     true,
+		},
+		{
+			name:     "guild timezone after the clocks spring forward",
+			settings: models.GuildSettings{Timezone: "Europe/Berlin", QuietHoursStart: "22:00", QuietHoursEnd: "07:00"},
+			now:      utc(2025, 3, 30, 5, 30), // 07:30 CEST
+		},
+		{
+			name:     "invalid timezone falls back to UTC",
+			settings: models.GuildSettings{Timezone: "Not/A_Zone", QuietHoursStart: "22:00", QuietHoursEnd: "07:00"},
+			now:      utc(2025, 6, 1, 6, 30),
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inQuietHours(&tt.settings, tt.now); got != tt.want {
+				t.Errorf("inQuietHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowMentions(t *testing.T) {
+	tests := []struct {
+		name      string
+		contents  []string
+		pinged    map[string]bool
+		wantRoles []string
+		wantUsers []string
+	}{
+		{name: "no mentions", contents: []string{"New post!"}},
+		{
+			name:      "same role in several notifications",
+			contents:  []string{"<@&1> live now", "<@&1> new post", "<@&2> <@&1>"},
+			wantRoles: []string{"1", "2"},
+		},
+		{
+			name:      "users and roles",
+			contents:  []string{"<@10> <@!11> <@&1>"},
+			wantRoles: []string{"1"},
+			wantUsers: []string{"10", "11"},
+		},
+		{
+			name:      "already pinged by an earlier message",
+			contents:  []string{"<@&1> <@&2> <@10>"},
+			pinged:    map[string]bool{"role:1": true, "user:10": true},
+			wantRoles: []string{"2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed := &discordgo.MessageAllowedMentions{}
+			for _, content := range tt.contents {
+				allowMentions(allowed, content, tt.pinged)
+			}
+			if !slices.Equal(allowed.Roles, tt.wantRoles) {
+				t.Errorf("roles = %v, want %v", allowed.Roles, tt.wantRoles)
+			}
+			if !slices.Equal(allowed.Users, tt.wantUsers) {
+				t.Errorf("users = %v, want %v", allowed.Users, tt.wantUsers)
+			}
+		})
+	}
+}
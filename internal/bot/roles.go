@@ -0,0 +1,206 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	rolesNamespace = "roles"
+
+	// rolePickerButtonsPerMessage is the most buttons a message can hold (5 rows of 5).
+	rolePickerButtonsPerMessage = 25
+)
+
+// handleRolesCommand manages the self-service notification roles members can pick themselves.
+func (b *Bot) handleRolesCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	subcommand := i.ApplicationCommandData().Options[0]
+	switch subcommand.Name {
+	case "enable":
+		b.enableSubscriberRole(s, i, subcommand.Options[0].StringValue())
+	case "disable":
+		b.disableSubscriberRole(s, i, subcommand.Options[0].StringValue())
+	case "picker":
+		b.postRolePicker(s, i, subcommand.Options[0].ChannelValue(s).ID)
+	}
+}
+
+func (b *Bot) enableSubscriberRole(s *discordgo.Session, i *discordgo.InteractionCreate, username string) {
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || user == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Creator **%s** is not being monitored in this server.", username))
+		return
+	}
+	if user.SubscriberRoleID != "" {
+		b.editInteractionResponse(s, i, fmt.Sprintf("**%s** already has a notification role: %s", user.Username, getRoleName(user.SubscriberRoleID)))
+		return
+	}
+
+	mentionable := true
+	role, err := s.GuildRoleCreate(i.GuildID, &discordgo.RoleParams{
+		Name:        subscriberRoleName(user.Username),
+		Mentionable: &mentionable,
+	})
+	if err != nil {
+		log.Printf("Error creating notification role for %s in guild %s: %v", user.Username, i.GuildID, err)
+		b.editInteractionResponse(s, i, "Could not create the role. Please make sure the bot has the **Manage Roles** permission.")
+		return
+	}
+
+	if err := b.Repo.SetSubscriberRole(i.GuildID, user.UserID, role.ID); err != nil {
+		s.GuildRoleDelete(i.GuildID, role.ID)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error saving the notification role: %v", err))
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("✅ Created %s. Members who pick it will be mentioned in every notification for **%s**. Use `/roles picker` to post the role picker.", role.Mention(), user.Username))
+	b.recordAudit(s, i, user.Username, "", role.Mention())
+}
+
+func (b *Bot) disableSubscriberRole(s *discordgo.Session, i *discordgo.InteractionCreate, username string) {
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || user == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Creator **%s** is not being monitored in this server.", username))
+		return
+	}
+	if user.SubscriberRoleID == "" {
+		b.editInteractionResponse(s, i, fmt.Sprintf("**%s** has no notification role.", user.Username))
+		return
+	}
+
+	if err := b.Repo.SetSubscriberRole(i.GuildID, user.UserID, ""); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error removing the notification role: %v", err))
+		return
+	}
+	b.deleteSubscriberRole(s, user)
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("The notification role for **%s** has been deleted.", user.Username))
+	b.recordAudit(s, i, user.Username, getRoleName(user.SubscriberRoleID), "")
+}
+
+// deleteSubscriberRole removes the managed role of a creator from the guild.
+func (b *Bot) deleteSubscriberRole(s *discordgo.Session, user *models.MonitoredUser) {
+	if user.SubscriberRoleID == "" {
+		return
+	}
+	if err := s.GuildRoleDelete(user.GuildID, user.SubscriberRoleID); err != nil {
+		log.Printf("Could not delete notification role %s in guild %s: %v", user.SubscriberRoleID, user.GuildID, err)
+	}
+}
+
+// renameSubscriberRole renames the managed role of a creator after the creator changed their username.
+func (b *Bot) renameSubscriberRole(user models.MonitoredUser) {
+	if user.SubscriberRoleID == "" {
+		return
+	}
+	name := subscriberRoleName(user.Username)
+	if _, err := b.Session.GuildRoleEdit(user.GuildID, user.SubscriberRoleID, &discordgo.RoleParams{Name: name}); err != nil {
+		log.Printf("Could not rename notification role %s in guild %s: %v", user.SubscriberRoleID, user.GuildID, err)
+	}
+}
+
+// subscriberRoleName returns the name of the managed notification role of a creator.
+func subscriberRoleName(username string) string {
+	return embed.Truncate(username+" Notifications", 100)
+}
+
+// postRolePicker posts messages with one button per creator that has a notification role.
+func (b *Bot) postRolePicker(s *discordgo.Session, i *discordgo.InteractionCreate, channelID string) {
+	users, err := b.Repo.GetMonitoredUsersForGuild(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching monitored users: %v", err))
+		return
+	}
+
+	var buttons []discordgo.MessageComponent
+	for _, user := range users {
+		if user.SubscriberRoleID == "" {
+			continue
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    embed.Truncate(user.Username, 80),
+			Style:    discordgo.SecondaryButton,
+			CustomID: makeCustomID(rolesNamespace, "toggle", user.UserID),
+			Emoji:    &discordgo.ComponentEmoji{Name: "🔔"},
+		})
+	}
+	if len(buttons) == 0 {
+		b.editInteractionResponse(s, i, "No creator has a notification role yet. Use `/roles enable` first.")
+		return
+	}
+
+	for start := 0; start < len(buttons); start += rolePickerButtonsPerMessage {
+		chunk := buttons[start:min(start+rolePickerButtonsPerMessage, len(buttons))]
+
+		var rows []discordgo.MessageComponent
+		for row := 0; row < len(chunk); row += 5 {
+			rows = append(rows, discordgo.ActionsRow{Components: chunk[row:min(row+5, len(chunk))]})
+		}
+
+		msg := &discordgo.MessageSend{Components: rows}
+		if start == 0 {
+			msg.Embed = &discordgo.MessageEmbed{
+				Title:       "Notification Roles",
+				Description: "Click a creator to be mentioned when they post or go live. Click again to stop.",
+				Color:       0x03b2f8,
+			}
+		}
+		if _, err := s.ChannelMessageSendComplex(channelID, msg); err != nil {
+			log.Printf("Error posting role picker in channel %s: %v", channelID, err)
+			b.editInteractionResponse(s, i, fmt.Sprintf("Could not post the role picker in <#%s>: %v", channelID, err))
+			return
+		}
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("✅ Posted the role picker in <#%s>.", channelID))
+}
+
+// handleRolesComponent lets any member toggle a creator's notification role on themselves.
+func (b *Bot) handleRolesComponent(s *discordgo.Session, i *discordgo.InteractionCreate, action string, args []string) {
+	if action != "toggle" || len(args) == 0 || i.Member == nil {
+		return
+	}
+
+	user, err := b.Repo.GetMonitoredUser(i.GuildID, args[0])
+	if err != nil {
+		log.Printf("Error fetching monitored user %s: %v", args[0], err)
+		b.respondToInteraction(s, i, "An error occurred. Please try again later.", true)
+		return
+	}
+	if user == nil || user.SubscriberRoleID == "" {
+		b.respondToInteraction(s, i, "Notifications for this creator are no longer available.", true)
+		return
+	}
+
+	if slices.Contains(i.Member.Roles, user.SubscriberRoleID) {
+		err = s.GuildMemberRoleRemove(i.GuildID, i.Member.User.ID, user.SubscriberRoleID)
+		if err == nil {
+			b.respondToInteraction(s, i, fmt.Sprintf("🔕 You will no longer be mentioned for **%s**.", user.Username), true)
+		}
+	} else {
+		err = s.GuildMemberRoleAdd(i.GuildID, i.Member.User.ID, user.SubscriberRoleID)
+		if err == nil {
+			b.respondToInteraction(s, i, fmt.Sprintf("🔔 You will now be mentioned when **%s** posts or goes live.", user.Username), true)
+		}
+	}
+	if err != nil {
+		log.Printf("Error toggling notification role %s for member %s: %v", user.SubscriberRoleID, i.Member.User.ID, err)
+		b.respondToInteraction(s, i, "The bot could not update your roles. Please ask an admin to check its permissions.", true)
+	}
+}
@@ -0,0 +1,514 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const settingsNamespace = "settings"
+
+// handleSettingsCommand opens an ephemeral panel to configure one creator in place.
+func (b *Bot) handleSettingsCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	username := i.ApplicationCommandData().Options[0].StringValue()
+
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil {
+		log.Printf("Error fetching monitored user by username '%s': %v", username, err)
+		b.respondToInteraction(s, i, "An error occurred while looking up the creator.", true)
+		return
+	}
+	if user == nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Creator **%s** is not being monitored in this server.", username), true)
+		return
+	}
+
+	data := b.settingsPanel(user)
+	data.Flags = discordgo.MessageFlagsEphemeral
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	})
+	if err != nil {
+		log.Printf("Error sending settings panel: %v", err)
+	}
+}
+
+// settingsPanel renders the current settings of a creator with the controls to change them.
+func (b *Bot) settingsPanel(user *models.MonitoredUser) *discordgo.InteractionResponseData {
+	colors, _ := b.Repo.GetEmbedColors(user.GuildID, user.UserID)
+	formats, _ := b.Repo.GetNotificationFormats(user.GuildID, user.UserID)
+	if colors == nil {
+		colors = &models.UserEmbedColor{}
+	}
+	if formats == nil {
+		formats = &models.UserNotificationFormat{}
+	}
+
+	panel := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Settings for %s", user.Username),
+		URL:         fmt.Sprintf("https://fansly.com/%s", user.Username),
+		Description: "Use the controls below to change how this creator is announced.",
+		Color:       0x03b2f8,
+		Thumbnail:   &discordgo.MessageEmbedThumbnail{URL: user.AvatarLocation},
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name: "Posts",
+				Value: fmt.Sprintf("%s\nChannel: %s\nRole: %s\nColor: %s\nMessage: %s",
+					statusText(user.PostsEnabled), channelText(user.PostNotificationChannel), getRoleName(user.PostMentionRole),
+					orDefault(colorText(colors.PostEmbedColor)), orDefault(formatSummary(formats.PostMessageFormat))),
+				Inline: true,
+			},
+			{
+				Name: "Live",
+				Value: fmt.Sprintf("%s\nChannel: %s\nRole: %s\nColor: %s\nMessage: %s",
+					statusText(user.LiveEnabled), channelText(user.LiveNotificationChannel), getRoleName(user.LiveMentionRole),
+					orDefault(colorText(colors.LiveEmbedColor)), orDefault(formatSummary(formats.LiveMessageFormat))),
+				Inline: true,
+			},
+			{
+				Name:  "Profile Changes",
+				Value: statusText(user.ProfileEnabled),
+			},
+		},
+	}
+
+	noMinimum := 0
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				toggleButton("Posts", user.PostsEnabled, makeCustomID(settingsNamespace, "toggle", user.UserID, "posts")),
+				toggleButton("Live", user.LiveEnabled, makeCustomID(settingsNamespace, "toggle", user.UserID, "live")),
+				toggleButton("Profile", user.ProfileEnabled, makeCustomID(settingsNamespace, "toggle", user.UserID, "profile")),
+				discordgo.Button{
+					Label:    "Messages",
+					Style:    discordgo.SecondaryButton,
+					CustomID: makeCustomID(settingsNamespace, "formats", user.UserID),
+					Emoji:    &discordgo.ComponentEmoji{Name: "📝"},
+				},
+				discordgo.Button{
+					Label:    "Colors",
+					Style:    discordgo.SecondaryButton,
+					CustomID: makeCustomID(settingsNamespace, "colors", user.UserID),
+					Emoji:    &discordgo.ComponentEmoji{Name: "🎨"},
+				},
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:      discordgo.ChannelSelectMenu,
+					CustomID:      makeCustomID(settingsNamespace, "postchannel", user.UserID),
+					Placeholder:   "Post notification channel",
+					ChannelTypes:  deliveryChannelTypes,
+					DefaultValues: selectDefault(user.PostNotificationChannel, discordgo.SelectMenuDefaultValueChannel),
+				},
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:      discordgo.ChannelSelectMenu,
+					CustomID:      makeCustomID(settingsNamespace, "livechannel", user.UserID),
+					Placeholder:   "Live notification channel",
+					ChannelTypes:  deliveryChannelTypes,
+					DefaultValues: selectDefault(user.LiveNotificationChannel, discordgo.SelectMenuDefaultValueChannel),
+				},
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:      discordgo.RoleSelectMenu,
+					CustomID:      makeCustomID(settingsNamespace, "postrole", user.UserID),
+					Placeholder:   "Post mention role (none)",
+					MinValues:     &noMinimum,
+					MaxValues:     1,
+					DefaultValues: selectDefault(user.PostMentionRole, discordgo.SelectMenuDefaultValueRole),
+				},
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:      discordgo.RoleSelectMenu,
+					CustomID:      makeCustomID(settingsNamespace, "liverole", user.UserID),
+					Placeholder:   "Live mention role (none)",
+					MinValues:     &noMinimum,
+					MaxValues:     1,
+					DefaultValues: selectDefault(user.LiveMentionRole, discordgo.SelectMenuDefaultValueRole),
+				},
+			},
+		},
+	}
+
+	return &discordgo.InteractionResponseData{
+		Embeds:     []*discordgo.MessageEmbed{panel},
+		Components: components,
+	}
+}
+
+func (b *Bot) handleSettingsComponent(s *discordgo.Session, i *discordgo.InteractionCreate, action string, args []string) {
+	if len(args) == 0 {
+		log.Printf("Received settings component without creator: %s", i.MessageComponentData().CustomID)
+		return
+	}
+	user := b.settingsUser(s, i, args[0])
+	if user == nil {
+		return
+	}
+
+	values := i.MessageComponentData().Values
+	var selected string
+	if len(values) > 0 {
+		selected = values[0]
+	}
+
+	// The change is audited after the panel was updated to answer the interaction in time.
+	var err error
+	var oldValue, newValue string
+	switch action {
+	case "toggle":
+		if len(args) < 2 {
+			return
+		}
+		oldValue, newValue, err = b.toggleFromPanel(user, args[1])
+
+	case "postchannel":
+		if selected == "" {
+			break
+		}
+		err = b.Repo.UpdatePostChannel(user.GuildID, user.Username, selected)
+		oldValue, newValue = channelText(user.PostNotificationChannel), channelText(selected)
+
+	case "livechannel":
+		if selected == "" {
+			break
+		}
+		err = b.Repo.UpdateLiveChannel(user.GuildID, user.Username, selected)
+		oldValue, newValue = channelText(user.LiveNotificationChannel), channelText(selected)
+
+	case "postrole":
+		err = b.Repo.UpdatePostMentionRole(user.GuildID, user.Username, selected)
+		oldValue, newValue = roleText(user.PostMentionRole), roleText(selected)
+
+	case "liverole":
+		err = b.Repo.UpdateLiveMentionRole(user.GuildID, user.Username, selected)
+		oldValue, newValue = roleText(user.LiveMentionRole), roleText(selected)
+
+	case "formats":
+		b.openSettingsFormatsModal(s, i, user)
+		return
+
+	case "colors":
+		b.openSettingsColorsModal(s, i, user)
+		return
+
+	default:
+		log.Printf("Received unknown settings action: %s", action)
+		return
+	}
+
+	if err != nil {
+		log.Printf("Error updating settings of %s from panel: %v", user.Username, err)
+		b.respondToInteraction(s, i, fmt.Sprintf("Error updating settings: %v", err), true)
+		return
+	}
+	b.updateSettingsPanel(s, i, user.UserID)
+	if oldValue != newValue {
+		b.recordAudit(s, i, user.Username, oldValue, newValue)
+		if action == "postchannel" || action == "livechannel" {
+			b.prepareDeliveryThreads(user.GuildID, user.UserID)
+		}
+	}
+}
+
+// toggleFromPanel flips a notification type of a creator and returns the old and new state.
+func (b *Bot) toggleFromPanel(user *models.MonitoredUser, notifType string) (string, string, error) {
+	var previous bool
+	var err error
+	switch notifType {
+	case "posts":
+		previous = user.PostsEnabled
+		if previous {
+			err = b.Repo.DisablePostsByUsername(user.GuildID, user.Username)
+		} else {
+			err = b.Repo.EnablePostsByUsername(user.GuildID, user.Username)
+		}
+	case "live":
+		previous = user.LiveEnabled
+		if previous {
+			err = b.Repo.DisableLiveByUsername(user.GuildID, user.Username)
+			if err == nil && user.LiveEventID != "" {
+				b.endLiveEvent(*user)
+			}
+		} else {
+			err = b.Repo.EnableLiveByUsername(user.GuildID, user.Username)
+		}
+	case "profile":
+		previous = user.ProfileEnabled
+		err = b.Repo.SetProfileEnabledByUsername(user.GuildID, user.Username, !previous)
+	default:
+		return "", "", fmt.Errorf("unknown notification type %q", notifType)
+	}
+
+	return fmt.Sprintf("%s %s", notifType, enabledText(previous)), fmt.Sprintf("%s %s", notifType, enabledText(!previous)), err
+}
+
+func (b *Bot) openSettingsFormatsModal(s *discordgo.Session, i *discordgo.InteractionCreate, user *models.MonitoredUser) {
+	formats, _ := b.Repo.GetNotificationFormats(user.GuildID, user.UserID)
+	if formats == nil {
+		formats = &models.UserNotificationFormat{}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: makeCustomID(settingsNamespace, "formats", user.UserID),
+			Title:    embed.Truncate(fmt.Sprintf("Messages for %s", user.Username), 45),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "post_format",
+							Label:       "Post Notification Message",
+							Style:       discordgo.TextInputParagraph,
+							Placeholder: "e.g., Hey {postMention}, {username} just posted!",
+							Value:       formats.PostMessageFormat,
+							Required:    false,
+						},
+					},
+				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "live_format",
+							Label:       "Live Notification Message",
+							Style:       discordgo.TextInputParagraph,
+							Placeholder: "e.g., {liveMention}! {username} is now live!",
+							Value:       formats.LiveMessageFormat,
+							Required:    false,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding with modal: %v", err)
+	}
+}
+
+func (b *Bot) openSettingsColorsModal(s *discordgo.Session, i *discordgo.InteractionCreate, user *models.MonitoredUser) {
+	colors, _ := b.Repo.GetEmbedColors(user.GuildID, user.UserID)
+	if colors == nil {
+		colors = &models.UserEmbedColor{}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: makeCustomID(settingsNamespace, "colors", user.UserID),
+			Title:    embed.Truncate(fmt.Sprintf("Colors for %s", user.Username), 45),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "post_color",
+							Label:       "Post Embed Color (empty for default)",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "#5865F2",
+							Value:       colorText(colors.PostEmbedColor),
+							Required:    false,
+							MaxLength:   7,
+						},
+					},
+				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "live_color",
+							Label:       "Live Embed Color (empty for default)",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "#EB459E",
+							Value:       colorText(colors.LiveEmbedColor),
+							Required:    false,
+							MaxLength:   7,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding with modal: %v", err)
+	}
+}
+
+func (b *Bot) handleSettingsModal(s *discordgo.Session, i *discordgo.InteractionCreate, action string, args []string) {
+	if len(args) == 0 {
+		log.Printf("Received settings modal without creator: %s", i.ModalSubmitData().CustomID)
+		return
+	}
+	user := b.settingsUser(s, i, args[0])
+	if user == nil {
+		return
+	}
+	values := modalValues(i.ModalSubmitData())
+
+	// Changes are audited as old/new pairs once the panel was updated.
+	var changes [][2]string
+	switch action {
+	case "formats":
+		formats, err := b.Repo.GetNotificationFormats(user.GuildID, user.UserID)
+		if err != nil {
+			b.respondToInteraction(s, i, "Error fetching existing data. Please try again.", true)
+			return
+		}
+		if formats == nil {
+			formats = &models.UserNotificationFormat{GuildID: user.GuildID, UserID: user.UserID}
+		}
+		previous := *formats
+		formats.PostMessageFormat = values["post_format"]
+		formats.LiveMessageFormat = values["live_format"]
+
+		if err := b.Repo.UpsertNotificationFormats(formats); err != nil {
+			log.Printf("Error saving format to DB: %v", err)
+			b.respondToInteraction(s, i, "Failed to save the custom message format.", true)
+			return
+		}
+		changes = append(changes,
+			[2]string{previous.PostMessageFormat, formats.PostMessageFormat},
+			[2]string{previous.LiveMessageFormat, formats.LiveMessageFormat},
+		)
+
+	case "colors":
+		postColor, postErr := parseOptionalColor(values["post_color"])
+		liveColor, liveErr := parseOptionalColor(values["live_color"])
+		if postErr != nil || liveErr != nil {
+			b.respondToInteraction(s, i, "Invalid hex color format. Please use `#[6-digit code]`, for example: `#5865F2`.", true)
+			return
+		}
+
+		colors, err := b.Repo.GetEmbedColors(user.GuildID, user.UserID)
+		if err != nil {
+			b.respondToInteraction(s, i, "An error occurred while fetching color settings.", true)
+			return
+		}
+		if colors == nil {
+			colors = &models.UserEmbedColor{GuildID: user.GuildID, UserID: user.UserID}
+		}
+		previous := *colors
+		colors.PostEmbedColor = postColor
+		colors.LiveEmbedColor = liveColor
+
+		if err := b.Repo.UpsertEmbedColors(colors); err != nil {
+			log.Printf("Error upserting embed colors: %v", err)
+			b.respondToInteraction(s, i, "Failed to save the new color setting to the database.", true)
+			return
+		}
+		changes = append(changes,
+			[2]string{colorText(previous.PostEmbedColor), colorText(colors.PostEmbedColor)},
+			[2]string{colorText(previous.LiveEmbedColor), colorText(colors.LiveEmbedColor)},
+		)
+
+	default:
+		log.Printf("Received unknown settings modal: %s", action)
+		return
+	}
+
+	b.updateSettingsPanel(s, i, user.UserID)
+	for _, change := range changes {
+		if change[0] != change[1] {
+			b.recordAudit(s, i, user.Username, change[0], change[1])
+		}
+	}
+}
+
+// settingsUser loads the creator a panel belongs to, telling the user if they were removed meanwhile.
+func (b *Bot) settingsUser(s *discordgo.Session, i *discordgo.InteractionCreate, userID string) *models.MonitoredUser {
+	user, err := b.Repo.GetMonitoredUser(i.GuildID, userID)
+	if err != nil {
+		log.Printf("Error fetching monitored user %s: %v", userID, err)
+		b.respondToInteraction(s, i, "An error occurred while looking up the creator.", true)
+		return nil
+	}
+	if user == nil {
+		b.respondToInteraction(s, i, "This creator is no longer monitored in this server.", true)
+		return nil
+	}
+	return user
+}
+
+// updateSettingsPanel re-renders the panel in the message the interaction came from.
+func (b *Bot) updateSettingsPanel(s *discordgo.Session, i *discordgo.InteractionCreate, userID string) {
+	user := b.settingsUser(s, i, userID)
+	if user == nil {
+		return
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: b.settingsPanel(user),
+	})
+	if err != nil {
+		log.Printf("Error updating settings panel: %v", err)
+	}
+}
+
+func parseOptionalColor(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if !strings.HasPrefix(value, "#") {
+		value = "#" + value
+	}
+	if !hexColorRegex.MatchString(value) {
+		return 0, fmt.Errorf("invalid color %q", value)
+	}
+	return parseHexColor(value)
+}
+
+func toggleButton(label string, enabled bool, customID string) discordgo.Button {
+	style, state := discordgo.DangerButton, "Off"
+	if enabled {
+		style, state = discordgo.SuccessButton, "On"
+	}
+	return discordgo.Button{
+		Label:    fmt.Sprintf("%s: %s", label, state),
+		Style:    style,
+		CustomID: customID,
+	}
+}
+
+func selectDefault(id string, valueType discordgo.SelectMenuDefaultValueType) []discordgo.SelectMenuDefaultValue {
+	if id == "" || id == "0" {
+		return nil
+	}
+	return []discordgo.SelectMenuDefaultValue{{ID: id, Type: valueType}}
+}
+
+func statusText(enabled bool) string {
+	if enabled {
+		return "✅ Enabled"
+	}
+	return "❌ Disabled"
+}
+
+func formatSummary(format string) string {
+	if format == "" {
+		return ""
+	}
+	return "`" + embed.Truncate(strings.ReplaceAll(format, "\n", " "), 40) + "`"
+}
+
+func orDefault(value string) string {
+	if value == "" {
+		return "Default"
+	}
+	return value
+}
@@ -0,0 +1,289 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/api"
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// channelRenameLimit renames per channelRenameWindow is Discord's limit on renaming a channel.
+	channelRenameLimit  = 2
+	channelRenameWindow = 10 * time.Minute
+)
+
+// liveStream is a creator's ongoing stream as seen by the last monitoring cycle.
+type liveStream struct {
+	ViewerCount int
+	StartedAt   int64 // Unix seconds
+}
+
+// liveTracker remembers which creators are live, for status boards and stream history.
+type liveTracker struct {
+	mu      sync.RWMutex
+	streams map[string]liveStream
+	checked map[string]bool        // creators seen by at least one monitoring cycle
+	renames map[string][]time.Time // renames per channel within channelRenameWindow
+}
+
+func newLiveTracker() *liveTracker {
+	return &liveTracker{
+		streams: make(map[string]liveStream),
+		checked: make(map[string]bool),
+		renames: make(map[string][]time.Time),
+	}
+}
+
+// update records a creator's stream status and reports whether a stream may have ended since the
+// last cycle. The first cycle after startup reports offline creators as ended, since they may have
+// gone offline while the bot was down.
+func (t *liveTracker) update(creatorID string, streamInfo *api.StreamResponse) (ended bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, wasLive := t.streams[creatorID]
+	firstCheck := !t.checked[creatorID]
+	t.checked[creatorID] = true
+
+	stream := streamInfo.Response.Stream
+	if stream.Status != 2 {
+		delete(t.streams, creatorID)
+		return wasLive || firstCheck
+	}
+	t.streams[creatorID] = liveStream{ViewerCount: stream.ViewerCount, StartedAt: stream.StartedAt / 1000}
+	return false
+}
+
+// forget drops a creator that is no longer monitored.
+func (t *liveTracker) forget(creatorID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.streams, creatorID)
+	delete(t.checked, creatorID)
+}
+
+func (t *liveTracker) get(creatorID string) (liveStream, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	stream, ok := t.streams[creatorID]
+	return stream, ok
+}
+
+// allowRename reports whether a channel can be renamed without hitting Discord's rate limit, and
+// counts the rename if so.
+func (t *liveTracker) allowRename(channelID string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	recent := t.renames[channelID][:0]
+	for _, renamedAt := range t.renames[channelID] {
+		if now.Sub(renamedAt) < channelRenameWindow {
+			recent = append(recent, renamedAt)
+		}
+	}
+	if len(recent) >= channelRenameLimit {
+		t.renames[channelID] = recent
+		return false
+	}
+	t.renames[channelID] = append(recent, now)
+	return true
+}
+
+// requestStatusBoardUpdate asks updateStatusBoardsWhenDue to refresh the status boards. A request
+// made while another one is pending is merged into it.
+func (b *Bot) requestStatusBoardUpdate() {
+	select {
+	case b.statusBoardDue <- struct{}{}:
+	default:
+	}
+}
+
+// updateStatusBoardsWhenDue refreshes the status boards on request, one update at a time.
+func (b *Bot) updateStatusBoardsWhenDue() {
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-b.statusBoardDue:
+			b.updateStatusBoards()
+		}
+	}
+}
+
+// updateStatusBoards refreshes every guild's status board and live counter channel with the
+// results of the last monitoring cycle.
+func (b *Bot) updateStatusBoards() {
+	b.statusBoardMu.Lock()
+	defer b.statusBoardMu.Unlock()
+
+	guilds, err := b.Repo.GetStatusBoardSettings()
+	if err != nil {
+		log.Printf("[StatusBoard] Error fetching status boards: %v", err)
+		return
+	}
+
+	for _, settings := range guilds {
+		live, err := b.liveCreators(settings.GuildID)
+		if err != nil {
+			log.Printf("[StatusBoard] Error fetching monitored users for guild %s: %v", settings.GuildID, err)
+			continue
+		}
+		if settings.StatusBoardChannelID != "" {
+			b.updateStatusBoard(&settings, live)
+		}
+		if settings.LiveCounterChannelID != "" {
+			b.updateLiveCounter(settings.LiveCounterChannelID, len(live))
+		}
+	}
+}
+
+// liveCreators returns the guild's creators that are live, most viewers first.
+func (b *Bot) liveCreators(guildID string) ([]embed.LiveCreator, error) {
+	users, err := b.Repo.GetMonitoredUsersForGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	var live []embed.LiveCreator
+	for _, user := range users {
+		if stream, ok := b.live.get(user.UserID); ok {
+			live = append(live, embed.LiveCreator{Username: user.Username, ViewerCount: stream.ViewerCount, StartedAt: stream.StartedAt})
+		}
+	}
+	sort.Slice(live, func(a, c int) bool { return live[a].ViewerCount > live[c].ViewerCount })
+	return live, nil
+}
+
+// updateStatusBoard edits the board message, posting a new one if it was deleted.
+func (b *Bot) updateStatusBoard(settings *models.GuildSettings, live []embed.LiveCreator) {
+	embedMsg := embed.CreateStatusBoardEmbed(live)
+	if settings.StatusBoardMessageID != "" {
+		_, err := b.Session.ChannelMessageEditEmbed(settings.StatusBoardChannelID, settings.StatusBoardMessageID, embedMsg)
+		if err == nil {
+			return
+		}
+		var restErr *discordgo.RESTError
+		if !errors.As(err, &restErr) || restErr.Message == nil || restErr.Message.Code != discordgo.ErrCodeUnknownMessage {
+			log.Printf("[StatusBoard] Error updating status board in guild %s: %v", settings.GuildID, err)
+			return
+		}
+	}
+
+	msg, err := b.Session.ChannelMessageSendEmbed(settings.StatusBoardChannelID, embedMsg)
+	if err != nil {
+		log.Printf("[StatusBoard] Error posting status board in guild %s: %v", settings.GuildID, err)
+		return
+	}
+	if err := b.Repo.UpdateGuildSettings(settings.GuildID, map[string]any{"status_board_message_id": msg.ID}); err != nil {
+		log.Printf("[StatusBoard] Error saving status board message for guild %s: %v", settings.GuildID, err)
+	}
+}
+
+// updateLiveCounter renames the counter channel when the number of live creators changed.
+func (b *Bot) updateLiveCounter(channelID string, liveCount int) {
+	name := fmt.Sprintf("🔴 %d live", liveCount)
+	if liveCount == 0 {
+		name = "⚫ nobody live"
+	}
+
+	channel, err := b.channel(channelID)
+	if err != nil || channel.Name == name || !b.live.allowRename(channelID, time.Now()) {
+		return
+	}
+	_, err = b.Session.ChannelEditComplex(channelID, &discordgo.ChannelEdit{Name: name}, discordgo.WithRetryOnRatelimit(false))
+	if err != nil {
+		log.Printf("[StatusBoard] Error renaming live counter channel %s: %v", channelID, err)
+	}
+}
+
+func (b *Bot) handleStatusBoardCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	var channelID, counterID string
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "channel":
+			channelID = opt.ChannelValue(s).ID
+		case "counter_channel":
+			counterID = opt.ChannelValue(s).ID
+		}
+	}
+
+	// Hold off the monitoring cycle's update until the new board message ID is saved.
+	b.statusBoardMu.Lock()
+	defer b.statusBoardMu.Unlock()
+
+	previous, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching settings: %v", err))
+		return
+	}
+
+	updated := *previous
+	updated.StatusBoardChannelID, updated.LiveCounterChannelID = channelID, counterID
+	if channelID != previous.StatusBoardChannelID {
+		updated.StatusBoardMessageID = ""
+		if previous.StatusBoardMessageID != "" {
+			s.ChannelMessageDelete(previous.StatusBoardChannelID, previous.StatusBoardMessageID)
+		}
+	}
+
+	err = b.Repo.UpdateGuildSettings(i.GuildID, map[string]any{
+		"status_board_channel_id": updated.StatusBoardChannelID,
+		"status_board_message_id": updated.StatusBoardMessageID,
+		"live_counter_channel_id": updated.LiveCounterChannelID,
+	})
+	if err != nil {
+		log.Printf("Error updating status board for guild %s: %v", i.GuildID, err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating settings: %v", err))
+		return
+	}
+
+	if channelID == "" && counterID == "" {
+		b.editInteractionResponse(s, i, "The status board is disabled.")
+	} else {
+		live, err := b.liveCreators(i.GuildID)
+		if err != nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching monitored users: %v", err))
+			return
+		}
+		if channelID != "" {
+			b.updateStatusBoard(&updated, live)
+		}
+		if counterID != "" {
+			b.updateLiveCounter(counterID, len(live))
+		}
+		b.editInteractionResponse(s, i, fmt.Sprintf("✅ %s. It is refreshed every monitoring cycle.", describeStatusBoard(&updated)))
+	}
+	b.recordAudit(s, i, "", describeStatusBoard(previous), describeStatusBoard(&updated))
+}
+
+func describeStatusBoard(settings *models.GuildSettings) string {
+	switch {
+	case settings.StatusBoardChannelID != "" && settings.LiveCounterChannelID != "":
+		return fmt.Sprintf("Status board in %s, live counter %s", channelText(settings.StatusBoardChannelID), channelText(settings.LiveCounterChannelID))
+	case settings.StatusBoardChannelID != "":
+		return fmt.Sprintf("Status board in %s", channelText(settings.StatusBoardChannelID))
+	case settings.LiveCounterChannelID != "":
+		return fmt.Sprintf("Live counter %s", channelText(settings.LiveCounterChannelID))
+	default:
+		return "disabled"
+	}
+}
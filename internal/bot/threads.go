@@ -0,0 +1,264 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/NotiFansly/notifansly-bot/internal/embed"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	deliveryPosts = "posts"
+	deliveryLive  = "live"
+
+	// forumTagPosts and forumTagLive are applied to a creator's forum thread for the latest notification.
+	forumTagPosts = "Posts"
+	forumTagLive  = "Live"
+
+	// maxAppliedTags is the most tags a forum thread can have.
+	maxAppliedTags = 5
+)
+
+// deliveryChannelTypes are the channel types notifications can be sent to.
+var deliveryChannelTypes = []discordgo.ChannelType{
+	discordgo.ChannelTypeGuildText,
+	discordgo.ChannelTypeGuildNews,
+	discordgo.ChannelTypeGuildForum,
+	discordgo.ChannelTypeGuildPublicThread,
+	discordgo.ChannelTypeGuildPrivateThread,
+	discordgo.ChannelTypeGuildNewsThread,
+}
+
+// channel returns a channel from the state cache, falling back to the API.
+func (b *Bot) channel(channelID string) (*discordgo.Channel, error) {
+	if channel, err := b.Session.State.Channel(channelID); err == nil {
+		return channel, nil
+	}
+	return b.Session.Channel(channelID)
+}
+
+func isUnknownChannel(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeUnknownChannel
+}
+
+// configuredChannel returns the channel a creator's notifications of the given kind are configured for.
+func configuredChannel(user *models.MonitoredUser, kind string) string {
+	channelID := user.PostNotificationChannel
+	if kind == deliveryLive {
+		channelID = user.LiveNotificationChannel
+	}
+	if channelID == "" {
+		channelID = user.NotificationChannel
+	}
+	return channelID
+}
+
+// deliveryChannel resolves where a notification of the given kind is sent. Forum channels get one
+// thread per creator, and archived threads are reopened. If the target cannot be resolved the
+// configured channel is returned, so sending fails and is logged like before.
+func (b *Bot) deliveryChannel(user *models.MonitoredUser, kind string) string {
+	channelID := configuredChannel(user, kind)
+	channel, err := b.channel(channelID)
+	if err != nil {
+		return channelID
+	}
+
+	switch {
+	case channel.Type == discordgo.ChannelTypeGuildForum:
+		threadID, err := b.forumThread(user, channel, kind)
+		if err != nil {
+			log.Printf("Error preparing forum thread for %s in channel %s: %v", user.Username, channelID, err)
+			return channelID
+		}
+		return threadID
+	case channel.IsThread():
+		b.reopenThread(channel, nil)
+	}
+	return channelID
+}
+
+// prepareDeliveryThreads creates the forum threads of a creator right away, so they exist before
+// the first notification. It reloads the creator to reuse threads that already exist.
+func (b *Bot) prepareDeliveryThreads(guildID, userID string) {
+	user, err := b.Repo.GetMonitoredUser(guildID, userID)
+	if err != nil || user == nil {
+		return
+	}
+	for _, kind := range []string{deliveryPosts, deliveryLive} {
+		channel, err := b.channel(configuredChannel(user, kind))
+		if err != nil || channel.Type != discordgo.ChannelTypeGuildForum {
+			continue
+		}
+		channel = b.ensureForumTags(channel)
+		if _, err := b.forumThread(user, channel, ""); err != nil {
+			log.Printf("Error creating forum thread for %s in channel %s: %v", user.Username, channel.ID, err)
+		}
+	}
+}
+
+// forumThread returns the creator's thread in forum, reusing the thread of the other notification
+// kind if both go to the same forum, and creating a new one if it was deleted. The thread is
+// reopened and tagged for kind; an empty kind leaves its tags alone.
+func (b *Bot) forumThread(user *models.MonitoredUser, forum *discordgo.Channel, kind string) (string, error) {
+	for _, threadID := range []string{user.PostThreadID, user.LiveThreadID} {
+		if threadID == "" {
+			continue
+		}
+		thread, err := b.channel(threadID)
+		if err != nil {
+			if !isUnknownChannel(err) {
+				return "", err
+			}
+			continue
+		}
+		if thread.ParentID != forum.ID {
+			continue
+		}
+		b.reopenThread(thread, forumThreadTags(forum, thread.AppliedTags, kind))
+		b.saveForumThread(user, forum.ID, threadID)
+		return threadID, nil
+	}
+
+	thread, err := b.Session.ForumThreadStartComplex(forum.ID, &discordgo.ThreadStart{
+		Name:        embed.Truncate(user.Username, 100),
+		AppliedTags: forumThreadTags(forum, nil, kind),
+	}, &discordgo.MessageSend{
+		Content: fmt.Sprintf("Notifications for **%s** are posted in this thread. https://fansly.com/%s", user.Username, user.Username),
+	})
+	if err != nil {
+		return "", err
+	}
+	b.saveForumThread(user, forum.ID, thread.ID)
+	return thread.ID, nil
+}
+
+// saveForumThread records threadID for every notification kind that goes to forumID.
+func (b *Bot) saveForumThread(user *models.MonitoredUser, forumID, threadID string) {
+	postThreadID, liveThreadID := user.PostThreadID, user.LiveThreadID
+	if configuredChannel(user, deliveryPosts) == forumID {
+		postThreadID = threadID
+	}
+	if configuredChannel(user, deliveryLive) == forumID {
+		liveThreadID = threadID
+	}
+	if postThreadID == user.PostThreadID && liveThreadID == user.LiveThreadID {
+		return
+	}
+
+	if err := b.Repo.UpdateThreadIDs(user.GuildID, user.UserID, postThreadID, liveThreadID); err != nil {
+		log.Printf("Error saving forum thread for %s in guild %s: %v", user.Username, user.GuildID, err)
+		return
+	}
+	user.PostThreadID, user.LiveThreadID = postThreadID, liveThreadID
+}
+
+// forumThreadTags returns the tags a thread should have after a notification of kind: the tag of
+// that kind replaces the other one, and tags added by members are kept. It returns nil if nothing changes.
+func forumThreadTags(forum *discordgo.Channel, current []string, kind string) []string {
+	tagName, otherName := forumTagPosts, forumTagLive
+	if kind == deliveryLive {
+		tagName, otherName = forumTagLive, forumTagPosts
+	}
+	var tagID, otherID string
+	for _, tag := range forum.AvailableTags {
+		switch {
+		case strings.EqualFold(tag.Name, tagName):
+			tagID = tag.ID
+		case strings.EqualFold(tag.Name, otherName):
+			otherID = tag.ID
+		}
+	}
+	if kind == "" || tagID == "" || (slices.Contains(current, tagID) && !slices.Contains(current, otherID)) {
+		return nil
+	}
+
+	tags := []string{tagID}
+	for _, id := range current {
+		if id != tagID && id != otherID && len(tags) < maxAppliedTags {
+			tags = append(tags, id)
+		}
+	}
+	return tags
+}
+
+// ensureForumTags adds the Posts and Live tags to a forum if they are missing. Without the Manage
+// Channels permission the forum is returned unchanged and threads are simply not tagged.
+func (b *Bot) ensureForumTags(forum *discordgo.Channel) *discordgo.Channel {
+	tags := slices.Clone(forum.AvailableTags)
+	for _, name := range []string{forumTagPosts, forumTagLive} {
+		if !slices.ContainsFunc(tags, func(tag discordgo.ForumTag) bool { return strings.EqualFold(tag.Name, name) }) {
+			tags = append(tags, discordgo.ForumTag{Name: name})
+		}
+	}
+	if len(tags) == len(forum.AvailableTags) {
+		return forum
+	}
+
+	updated, err := b.Session.ChannelEditComplex(forum.ID, &discordgo.ChannelEdit{AvailableTags: &tags})
+	if err != nil {
+		log.Printf("Could not add notification tags to forum %s: %v", forum.ID, err)
+		return forum
+	}
+	return updated
+}
+
+// reopenThread unarchives a thread and applies tags in a single edit, if anything needs to change.
+func (b *Bot) reopenThread(thread *discordgo.Channel, tags []string) {
+	archived := thread.ThreadMetadata != nil && thread.ThreadMetadata.Archived
+	if !archived && tags == nil {
+		return
+	}
+
+	edit := &discordgo.ChannelEdit{}
+	if archived {
+		unarchived := false
+		edit.Archived = &unarchived
+	}
+	if tags != nil {
+		edit.AppliedTags = &tags
+	}
+	if _, err := b.Session.ChannelEditComplex(thread.ID, edit); err != nil {
+		log.Printf("Could not reopen thread %s: %v", thread.ID, err)
+	}
+}
+
+// renameForumThreads renames a creator's forum threads after the creator changed their username.
+func (b *Bot) renameForumThreads(user models.MonitoredUser) {
+	name := embed.Truncate(user.Username, 100)
+	threadIDs := []string{user.PostThreadID}
+	if user.LiveThreadID != user.PostThreadID {
+		threadIDs = append(threadIDs, user.LiveThreadID)
+	}
+	for _, threadID := range threadIDs {
+		if threadID == "" {
+			continue
+		}
+		thread, err := b.channel(threadID)
+		if err != nil || thread.Name == name {
+			continue
+		}
+
+		// Archived threads must be reopened to be renamed.
+		edit := &discordgo.ChannelEdit{Name: name}
+		if thread.ThreadMetadata != nil && thread.ThreadMetadata.Archived {
+			unarchived := false
+			edit.Archived = &unarchived
+		}
+		if _, err := b.Session.ChannelEditComplex(threadID, edit); err != nil {
+			log.Printf("Could not rename forum thread %s of %s: %v", threadID, user.Username, err)
+		}
+	}
+}
+
+// reopenThreadByID unarchives channelID if it is an archived thread.
+func (b *Bot) reopenThreadByID(channelID string) {
+	if channel, err := b.channel(channelID); err == nil && channel.IsThread() {
+		b.reopenThread(channel, nil)
+	}
+}
@@ -0,0 +1,183 @@
+// Package charts renders creator activity as PNG images.
+package charts
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+const (
+	width  = 8 * vg.Inch
+	height = 4 * vg.Inch
+)
+
+var accent = color.RGBA{R: 0x03, G: 0xb2, B: 0xf8, A: 0xff}
+
+// PostsPerDay renders a bar chart of the posts made on each of the last days days.
+func PostsPerDay(username string, posts []models.PostEvent, days int, loc *time.Location, now time.Time) ([]byte, error) {
+	today := now.In(loc)
+	first := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -(days - 1))
+
+	counts := make(plotter.Values, days)
+	for _, post := range posts {
+		day := time.Unix(post.PostedAt, 0).In(loc)
+		idx := int(time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc).Sub(first).Hours()+12) / 24
+		if idx >= 0 && idx < days {
+			counts[idx]++
+		}
+	}
+
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("Posts per day · %s", username)
+	p.Y.Label.Text = "Posts"
+	p.Y.Min = 0
+
+	bars, err := plotter.NewBarChart(counts, width/vg.Length(days+2)*0.8)
+	if err != nil {
+		return nil, err
+	}
+	bars.Color = accent
+	bars.LineStyle.Width = 0
+	p.Add(bars)
+
+	// Label about eight days so the dates stay readable for long periods.
+	step := max(days/8, 1)
+	var ticks []plot.Tick
+	for idx := 0; idx < days; idx++ {
+		tick := plot.Tick{Value: float64(idx)}
+		if (days-1-idx)%step == 0 {
+			tick.Label = first.AddDate(0, 0, idx).Format("Jan 2")
+		}
+		ticks = append(ticks, tick)
+	}
+	p.X.Tick.Marker = plot.ConstantTicks(ticks)
+
+	return render(p)
+}
+
+// liveGrid holds hours live per weekday (rows, Sunday first) and hour of day (columns).
+type liveGrid [7][24]float64
+
+func (g *liveGrid) Dims() (c, r int)   { return 24, 7 }
+func (g *liveGrid) Z(c, r int) float64 { return g[r][c] }
+func (g *liveGrid) X(c int) float64    { return float64(c) }
+func (g *liveGrid) Y(r int) float64    { return float64(r) }
+
+// LiveHeatmap renders how many hours a creator was live in each hour of the week.
+func LiveHeatmap(username string, sessions []models.StreamSession, loc *time.Location, now time.Time) ([]byte, error) {
+	var grid liveGrid
+	var most float64
+	for _, session := range sessions {
+		start := time.Unix(session.StartedAt, 0).In(loc)
+		end := now
+		if session.EndedAt != 0 {
+			end = time.Unix(session.EndedAt, 0)
+		}
+		for hour := start.Truncate(time.Hour); hour.Before(end); hour = hour.Add(time.Hour) {
+			from, to := maxTime(hour, start), minTime(hour.Add(time.Hour), end)
+			local := hour.In(loc)
+			grid[local.Weekday()][local.Hour()] += to.Sub(from).Hours()
+			most = max(most, grid[local.Weekday()][local.Hour()])
+		}
+	}
+
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("Hours live by weekday and hour (%s) · %s", loc, username)
+	p.X.Label.Text = "Hour of day"
+
+	heatmap := plotter.NewHeatMap(&grid, heatPalette())
+	heatmap.Min, heatmap.Max = 0, max(most, 1)
+	p.Add(heatmap)
+
+	var hourTicks []plot.Tick
+	for hour := 0; hour < 24; hour += 3 {
+		hourTicks = append(hourTicks, plot.Tick{Value: float64(hour), Label: fmt.Sprintf("%02d:00", hour)})
+	}
+	p.X.Tick.Marker = plot.ConstantTicks(hourTicks)
+
+	var dayTicks []plot.Tick
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		dayTicks = append(dayTicks, plot.Tick{Value: float64(day), Label: day.String()[:3]})
+	}
+	p.Y.Tick.Marker = plot.ConstantTicks(dayTicks)
+
+	return render(p)
+}
+
+// StreamViewers renders the viewer count over the course of one stream.
+func StreamViewers(username string, samples []models.StreamViewerSample, loc *time.Location) ([]byte, error) {
+	points := make(plotter.XYs, len(samples))
+	for idx, sample := range samples {
+		points[idx].X = float64(sample.SampledAt)
+		points[idx].Y = float64(sample.Viewers)
+	}
+
+	p := plot.New()
+	started := time.Unix(samples[0].StartedAt, 0).In(loc)
+	p.Title.Text = fmt.Sprintf("Viewers · %s · stream of %s", username, started.Format("Jan 2 15:04 MST"))
+	p.Y.Label.Text = "Viewers"
+	p.Y.Min = 0
+	p.X.Tick.Marker = plot.TimeTicks{
+		Format: "15:04",
+		Time:   func(t float64) time.Time { return time.Unix(int64(t), 0).In(loc) },
+	}
+
+	line, err := plotter.NewLine(points)
+	if err != nil {
+		return nil, err
+	}
+	line.Color = accent
+	line.Width = vg.Points(2)
+	p.Add(line, plotter.NewGrid())
+
+	return render(p)
+}
+
+// colors is a palette.Palette of fixed colors.
+type colors []color.Color
+
+func (c colors) Colors() []color.Color { return c }
+
+// heatPalette runs from pale yellow for hours never live to red for the most active hours.
+func heatPalette() palette.Palette {
+	heat := palette.Heat(12, 1).Colors()
+	reversed := make(colors, len(heat))
+	for idx, c := range heat {
+		reversed[len(heat)-1-idx] = c
+	}
+	return reversed
+}
+
+func render(p *plot.Plot) ([]byte, error) {
+	writer, err := p.WriterTo(width, height, "png")
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := writer.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
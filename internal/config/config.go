@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +13,7 @@ var (
 	// Bot configuration
 	DiscordToken string
 	FanslyToken  string
+	FanslyTokens []string // FANSLY_TOKEN plus any extra accounts from FANSLY_TOKENS
 	UserAgent    string
 	AppID        string
 	PublicKey    string
@@ -24,15 +26,29 @@ var (
 	PostgresURL  string
 
 	// Application settings
-	Debug                       bool
-	MonitorIntervalSeconds      int
-	StatusUpdateIntervalMinutes int
-	AvatarRefreshIntervalHours  int
-	MonitorWorkerCount          int
-	MaxMonitoredUsersPerGuild   int
+	Debug                         bool
+	MonitorIntervalSeconds        int
+	StatusUpdateIntervalMinutes   int
+	AccountRefreshIntervalMinutes int
+	MonitorWorkerCount            int
+	MaxMonitoredUsersPerGuild     int
+	MaxFollowsPerUser             int
 
 	ApiRequestsPerSecond float64
 	ApiBurst             int
+
+	// Optional HTTP server for receiving interactions instead of (or next to) the gateway
+	HTTPListenAddr   string
+	InteractionsPath string
+	// PublicURL is where the HTTP server is reachable from outside; setting it enables calendar feeds
+	PublicURL    string
+	CalendarPath string
+
+	// Fansly API endpoints, overridable to point the client at a local mock server
+	FanslyAPIBaseURL     string
+	FanslyWebsocketURL   string
+	FanslyWebURL         string // site the check key is read from, also sent as origin and referer
+	FanslyRequestTimeout int    // seconds
 )
 
 func Load() {
@@ -49,8 +65,9 @@ func Load() {
 	PublicKey = os.Getenv("PUBLIC_KEY")
 	LogChannelID = os.Getenv("LOG_CHANNEL_ID")
 	BotOwnerID = os.Getenv("BOT_OWNER_ID")
+	FanslyTokens = getFanslyTokens()
 
-	if DiscordToken == "" || FanslyToken == "" || UserAgent == "" || AppID == "" || PublicKey == "" {
+	if DiscordToken == "" || len(FanslyTokens) == 0 || UserAgent == "" || AppID == "" || PublicKey == "" {
 		log.Fatal("Missing required environment variables")
 	}
 
@@ -74,14 +91,53 @@ func Load() {
 	debugStr := os.Getenv("DEBUG")
 	Debug, _ = strconv.ParseBool(debugStr)
 
-	MonitorIntervalSeconds = getEnvAsInt("MONITOR_INTERVAL_SECONDS", 120)            // Default: 2 minutes
-	StatusUpdateIntervalMinutes = getEnvAsInt("STATUS_UPDATE_INTERVAL_MINUTES", 120) // Default: 2 hours
-	AvatarRefreshIntervalHours = getEnvAsInt("AVATAR_REFRESH_INTERVAL_HOURS", 144)   // Default: 6 days (6 * 24)
-	MonitorWorkerCount = getEnvAsInt("MONITOR_WORKER_COUNT", 10)                     // Default: 10 workers
+	MonitorIntervalSeconds = getEnvAsInt("MONITOR_INTERVAL_SECONDS", 120)               // Default: 2 minutes
+	StatusUpdateIntervalMinutes = getEnvAsInt("STATUS_UPDATE_INTERVAL_MINUTES", 120)    // Default: 2 hours
+	AccountRefreshIntervalMinutes = getEnvAsInt("ACCOUNT_REFRESH_INTERVAL_MINUTES", 60) // Default: 1 hour
+	if _, ok := os.LookupEnv("ACCOUNT_REFRESH_INTERVAL_MINUTES"); !ok {
+		if hours := getEnvAsInt("AVATAR_REFRESH_INTERVAL_HOURS", 0); hours > 0 {
+			log.Println("Warning: AVATAR_REFRESH_INTERVAL_HOURS is deprecated, use ACCOUNT_REFRESH_INTERVAL_MINUTES instead")
+			AccountRefreshIntervalMinutes = hours * 60
+		}
+	}
+	MonitorWorkerCount = getEnvAsInt("MONITOR_WORKER_COUNT", 10) // Default: 10 workers
 	MaxMonitoredUsersPerGuild = getEnvAsInt("MAX_MONITORED_USERS_PER_GUILD", 5)
+	MaxFollowsPerUser = getEnvAsInt("MAX_FOLLOWS_PER_USER", 10) // Direct-message subscriptions per Discord user
 
 	ApiRequestsPerSecond = getEnvAsFloat64("API_REQUESTS_PER_SECOND", 2.0)
 	ApiBurst = getEnvAsInt("API_BURST", 5)
+
+	HTTPListenAddr = os.Getenv("HTTP_LISTEN_ADDR") // e.g. ":8080"; empty disables the HTTP server
+	InteractionsPath = getEnvAsString("INTERACTIONS_PATH", "/interactions")
+	PublicURL = strings.TrimSuffix(os.Getenv("PUBLIC_URL"), "/") // e.g. "https://bot.example.com"
+	CalendarPath = strings.TrimSuffix(getEnvAsString("CALENDAR_PATH", "/calendar"), "/")
+
+	FanslyAPIBaseURL = getEnvAsString("FANSLY_API_BASE_URL", "https://apiv3.fansly.com")
+	FanslyWebsocketURL = getEnvAsString("FANSLY_WEBSOCKET_URL", "wss://wsv3.fansly.com/")
+	FanslyWebURL = strings.TrimSuffix(getEnvAsString("FANSLY_WEB_URL", "https://fansly.com"), "/")
+	FanslyRequestTimeout = getEnvAsInt("FANSLY_REQUEST_TIMEOUT_SECONDS", 15)
+}
+
+func getEnvAsString(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getFanslyTokens combines FANSLY_TOKEN with the comma-separated FANSLY_TOKENS list, skipping duplicates.
+func getFanslyTokens() []string {
+	var tokens []string
+	seen := make(map[string]bool)
+	for _, token := range append([]string{FanslyToken}, strings.Split(os.Getenv("FANSLY_TOKENS"), ",")...) {
+		token = strings.TrimSpace(token)
+		if token == "" || seen[token] {
+			continue
+		}
+		seen[token] = true
+		tokens = append(tokens, token)
+	}
+	return tokens
 }
 
 func getEnvAsFloat64(key string, fallback float64) float64 {
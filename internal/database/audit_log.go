@@ -0,0 +1,25 @@
+package database
+
+import (
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+)
+
+func (r *Repository) AddAuditLogEntry(entry *models.AuditLogEntry) error {
+	return WithRetry(func() error {
+		return r.db.Create(entry).Error
+	})
+}
+
+// GetAuditLogEntries returns the most recent audit entries of a guild, newest first.
+// If target is set, only changes to that creator or setting are returned.
+func (r *Repository) GetAuditLogEntries(guildID, target string, limit int) ([]models.AuditLogEntry, error) {
+	var entries []models.AuditLogEntry
+	err := WithRetry(func() error {
+		query := r.db.Where("guild_id = ?", guildID)
+		if target != "" {
+			query = query.Where("LOWER(target) = LOWER(?)", target)
+		}
+		return query.Order("created_at DESC, id DESC").Limit(limit).Find(&entries).Error
+	})
+	return entries, err
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordStreamSample adds one monitoring cycle's viewer count to the stream that started at
+// startedAt, creating the session on its first sample. Other sessions of the creator that are
+// still open are closed, in case a stream ended between two cycles and a new one began.
+func (r *Repository) RecordStreamSample(userID string, startedAt int64, viewers int, now int64) error {
+	return WithRetry(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			err := tx.Model(&models.StreamSession{}).
+				Where("user_id = ? AND ended_at = 0 AND started_at <> ?", userID, startedAt).
+				Update("ended_at", now).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Create(&models.StreamViewerSample{UserID: userID, StartedAt: startedAt, SampledAt: now, Viewers: viewers}).Error
+			if err != nil {
+				return err
+			}
+
+			var sessions []models.StreamSession
+			if err := tx.Where("user_id = ? AND started_at = ?", userID, startedAt).Limit(1).Find(&sessions).Error; err != nil {
+				return err
+			}
+			if len(sessions) == 0 {
+				return tx.Create(&models.StreamSession{
+					UserID:        userID,
+					StartedAt:     startedAt,
+					PeakViewers:   viewers,
+					ViewerTotal:   int64(viewers),
+					ViewerSamples: 1,
+				}).Error
+			}
+
+			session := sessions[0]
+			return tx.Model(&session).Updates(map[string]any{
+				"ended_at":       0,
+				"peak_viewers":   max(session.PeakViewers, viewers),
+				"viewer_total":   session.ViewerTotal + int64(viewers),
+				"viewer_samples": session.ViewerSamples + 1,
+			}).Error
+		})
+	})
+}
+
+// EndStreamSessions closes every open stream session of a creator.
+func (r *Repository) EndStreamSessions(userID string, endedAt int64) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.StreamSession{}).
+			Where("user_id = ? AND ended_at = 0", userID).
+			Update("ended_at", endedAt).Error
+	})
+}
+
+// GetOpenStreamSessionCreatorIDs returns the creators that have a stream session without an end.
+func (r *Repository) GetOpenStreamSessionCreatorIDs() ([]string, error) {
+	var creatorIDs []string
+	err := WithRetry(func() error {
+		return r.db.Model(&models.StreamSession{}).Where("ended_at = 0").Distinct().Pluck("user_id", &creatorIDs).Error
+	})
+	return creatorIDs, err
+}
+
+// GetStreamSessions returns a creator's streams that started at or after since, oldest first.
+func (r *Repository) GetStreamSessions(userID string, since int64) ([]models.StreamSession, error) {
+	var sessions []models.StreamSession
+	err := WithRetry(func() error {
+		return r.db.Where("user_id = ? AND started_at >= ?", userID, since).Order("started_at").Find(&sessions).Error
+	})
+	return sessions, err
+}
+
+// GetStreamViewerSamples returns the viewer counts recorded during a stream, oldest first.
+func (r *Repository) GetStreamViewerSamples(userID string, startedAt int64) ([]models.StreamViewerSample, error) {
+	var samples []models.StreamViewerSample
+	err := WithRetry(func() error {
+		return r.db.Where("user_id = ? AND started_at = ?", userID, startedAt).Order("sampled_at").Find(&samples).Error
+	})
+	return samples, err
+}
+
+// DeleteStreamViewerSamplesBefore removes viewer counts sampled before cutoff and returns how many were removed.
+func (r *Repository) DeleteStreamViewerSamplesBefore(cutoff int64) (int64, error) {
+	var deleted int64
+	err := WithRetry(func() error {
+		result := r.db.Where("sampled_at < ?", cutoff).Delete(&models.StreamViewerSample{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+// RecordPostEvents stores posts of a creator, ignoring posts that are already recorded.
+func (r *Repository) RecordPostEvents(events []models.PostEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return WithRetry(func() error {
+		return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&events).Error
+	})
+}
+
+// GetPostEvents returns a creator's posts published at or after since, oldest first.
+func (r *Repository) GetPostEvents(userID string, since int64) ([]models.PostEvent, error) {
+	var events []models.PostEvent
+	err := WithRetry(func() error {
+		return r.db.Where("user_id = ? AND posted_at >= ?", userID, since).Order("posted_at").Find(&events).Error
+	})
+	return events, err
+}
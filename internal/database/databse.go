@@ -101,6 +101,20 @@ func Init(dbType string, connString string) error {
 		&models.APIHealthStat{},
 		&models.UserEmbedColor{},
 		&models.UserNotificationFormat{},
+		&models.GuildSettings{},
+		&models.UsernameAlias{},
+		&models.CreatorProfileSnapshot{},
+		&models.GuildManagerRole{},
+		&models.CommandPermission{},
+		&models.AuditLogEntry{},
+		&models.PendingAdd{},
+		&models.DirectSubscription{},
+		&models.HeldNotification{},
+		&models.DigestItem{},
+		&models.PostBurst{},
+		&models.StreamSession{},
+		&models.PostEvent{},
+		&models.StreamViewerSample{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database schema: %w", err)
@@ -159,6 +173,10 @@ func addIndexes() error {
 	if err != nil {
 		return err
 	}
+	err = DB.Exec("CREATE INDEX IF NOT EXISTS idx_username_aliases_username ON username_aliases(username)").Error
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
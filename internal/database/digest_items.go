@@ -0,0 +1,38 @@
+package database
+
+import (
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+)
+
+func (r *Repository) AddDigestItem(item *models.DigestItem) error {
+	return WithRetry(func() error {
+		return r.db.Create(item).Error
+	})
+}
+
+// GetDigestItems returns the posts waiting for a guild's next digest, oldest first.
+func (r *Repository) GetDigestItems(guildID string) ([]models.DigestItem, error) {
+	var items []models.DigestItem
+	err := WithRetry(func() error {
+		return r.db.Where("guild_id = ?", guildID).Order("id").Find(&items).Error
+	})
+	return items, err
+}
+
+// GetGuildsWithDigestItems returns the IDs of guilds that have posts waiting for a digest.
+func (r *Repository) GetGuildsWithDigestItems() ([]string, error) {
+	var guildIDs []string
+	err := WithRetry(func() error {
+		return r.db.Model(&models.DigestItem{}).Distinct().Pluck("guild_id", &guildIDs).Error
+	})
+	return guildIDs, err
+}
+
+func (r *Repository) DeleteDigestItems(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return WithRetry(func() error {
+		return r.db.Delete(&models.DigestItem{}, ids).Error
+	})
+}
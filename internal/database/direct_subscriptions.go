@@ -0,0 +1,122 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertDirectSubscription creates or updates a personal subscription and resumes delivery.
+func (r *Repository) UpsertDirectSubscription(sub *models.DirectSubscription) error {
+	return WithRetry(func() error {
+		return r.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "discord_user_id"}, {Name: "creator_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"username", "avatar_location", "posts_enabled", "live_enabled", "paused"}),
+		}).Create(sub).Error
+	})
+}
+
+func (r *Repository) GetDirectSubscription(discordUserID, creatorID string) (*models.DirectSubscription, error) {
+	var subs []models.DirectSubscription
+	err := WithRetry(func() error {
+		return r.db.Where("discord_user_id = ? AND creator_id = ?", discordUserID, creatorID).Limit(1).Find(&subs).Error
+	})
+	if err != nil || len(subs) == 0 {
+		return nil, err
+	}
+	return &subs[0], nil
+}
+
+func (r *Repository) GetDirectSubscriptionsForUser(discordUserID string) ([]models.DirectSubscription, error) {
+	var subs []models.DirectSubscription
+	err := WithRetry(func() error {
+		return r.db.Where("discord_user_id = ?", discordUserID).Order("username").Find(&subs).Error
+	})
+	return subs, err
+}
+
+// GetActiveDirectSubscriptions returns all subscriptions whose DMs are not paused.
+func (r *Repository) GetActiveDirectSubscriptions() ([]models.DirectSubscription, error) {
+	var subs []models.DirectSubscription
+	err := WithRetry(func() error {
+		return r.db.Where("paused = ?", false).Find(&subs).Error
+	})
+	return subs, err
+}
+
+// GetDirectSubscriptionCreatorIDs returns the IDs of every creator with at least one DM subscription.
+func (r *Repository) GetDirectSubscriptionCreatorIDs() ([]string, error) {
+	var creatorIDs []string
+	err := WithRetry(func() error {
+		return r.db.Model(&models.DirectSubscription{}).Distinct().Pluck("creator_id", &creatorIDs).Error
+	})
+	return creatorIDs, err
+}
+
+// UpdateDirectSubscriptionAccountInfo stores a creator's current username and avatar in every DM
+// subscription to them.
+func (r *Repository) UpdateDirectSubscriptionAccountInfo(creatorID, username, avatarLocation string) error {
+	updates := map[string]any{}
+	if username != "" {
+		updates["username"] = username
+	}
+	if avatarLocation != "" {
+		updates["avatar_location"] = avatarLocation
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return WithRetry(func() error {
+		return r.db.Model(&models.DirectSubscription{}).Where("creator_id = ?", creatorID).Updates(updates).Error
+	})
+}
+
+func (r *Repository) CountDirectSubscriptions(discordUserID string) (int64, error) {
+	var count int64
+	err := WithRetry(func() error {
+		return r.db.Model(&models.DirectSubscription{}).Where("discord_user_id = ?", discordUserID).Count(&count).Error
+	})
+	return count, err
+}
+
+// DeleteDirectSubscriptionByUsername removes a user's subscription to the creator with the given username.
+func (r *Repository) DeleteDirectSubscriptionByUsername(discordUserID, username string) error {
+	return WithRetry(func() error {
+		result := r.db.Where("discord_user_id = ? AND LOWER(username) = ?", discordUserID, strings.ToLower(username)).
+			Delete(&models.DirectSubscription{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("you are not following this creator")
+		}
+		return nil
+	})
+}
+
+func (r *Repository) UpdateDirectLastPostID(discordUserID, creatorID, postID string) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.DirectSubscription{}).
+			Where("discord_user_id = ? AND creator_id = ?", discordUserID, creatorID).
+			Update("last_post_id", postID).Error
+	})
+}
+
+func (r *Repository) UpdateDirectLastStreamStart(discordUserID, creatorID string, timestamp int64) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.DirectSubscription{}).
+			Where("discord_user_id = ? AND creator_id = ?", discordUserID, creatorID).
+			Update("last_stream_start", timestamp).Error
+	})
+}
+
+// PauseDirectSubscriptions stops DM delivery to a user, e.g. after they closed their DMs.
+func (r *Repository) PauseDirectSubscriptions(discordUserID string) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.DirectSubscription{}).
+			Where("discord_user_id = ?", discordUserID).
+			Update("paused", true).Error
+	})
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetGuildSettings returns the settings of a guild, or the defaults if none were saved yet.
+func (r *Repository) GetGuildSettings(guildID string) (*models.GuildSettings, error) {
+	var settings []models.GuildSettings
+	err := WithRetry(func() error {
+		return r.db.Where("guild_id = ?", guildID).Limit(1).Find(&settings).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		return &models.GuildSettings{GuildID: guildID}, nil
+	}
+	return &settings[0], nil
+}
+
+// UpdateGuildSettings updates the given columns of a guild's settings, creating the row if needed.
+func (r *Repository) UpdateGuildSettings(guildID string, updates map[string]any) error {
+	return WithRetry(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where(models.GuildSettings{GuildID: guildID}).FirstOrCreate(&models.GuildSettings{}).Error; err != nil {
+				return err
+			}
+			return tx.Model(&models.GuildSettings{}).Where("guild_id = ?", guildID).Updates(updates).Error
+		})
+	})
+}
+
+// GetGuildSettingsByCalendarToken returns the settings of the guild whose calendar feeds use token,
+// or (nil, nil) if no guild does.
+func (r *Repository) GetGuildSettingsByCalendarToken(token string) (*models.GuildSettings, error) {
+	var settings []models.GuildSettings
+	err := WithRetry(func() error {
+		return r.db.Where("calendar_token = ?", token).Limit(1).Find(&settings).Error
+	})
+	if err != nil || len(settings) == 0 {
+		return nil, err
+	}
+	return &settings[0], nil
+}
+
+// GetStatusBoardSettings returns the settings of every guild with a status board or live counter channel.
+func (r *Repository) GetStatusBoardSettings() ([]models.GuildSettings, error) {
+	var settings []models.GuildSettings
+	err := WithRetry(func() error {
+		return r.db.Where("status_board_channel_id <> '' OR live_counter_channel_id <> ''").Find(&settings).Error
+	})
+	return settings, err
+}
+
+func (r *Repository) AddManagerRole(guildID, roleID string) error {
+	return WithRetry(func() error {
+		return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.GuildManagerRole{GuildID: guildID, RoleID: roleID}).Error
+	})
+}
+
+func (r *Repository) RemoveManagerRole(guildID, roleID string) error {
+	return WithRetry(func() error {
+		result := r.db.Where("guild_id = ? AND role_id = ?", guildID, roleID).Delete(&models.GuildManagerRole{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("role is not a manager role")
+		}
+		return nil
+	})
+}
+
+// GetManagerRoles returns the IDs of all manager roles of a guild.
+func (r *Repository) GetManagerRoles(guildID string) ([]string, error) {
+	var roleIDs []string
+	err := WithRetry(func() error {
+		return r.db.Model(&models.GuildManagerRole{}).Where("guild_id = ?", guildID).Pluck("role_id", &roleIDs).Error
+	})
+	return roleIDs, err
+}
+
+func (r *Repository) AddCommandPermission(guildID, command, roleID string) error {
+	return WithRetry(func() error {
+		return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.CommandPermission{GuildID: guildID, Command: command, RoleID: roleID}).Error
+	})
+}
+
+// RemoveCommandPermissions removes a role override for a command, or every override of the
+// command if roleID is empty.
+func (r *Repository) RemoveCommandPermissions(guildID, command, roleID string) error {
+	return WithRetry(func() error {
+		query := r.db.Where("guild_id = ? AND command = ?", guildID, command)
+		if roleID != "" {
+			query = query.Where("role_id = ?", roleID)
+		}
+		result := query.Delete(&models.CommandPermission{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("no matching permission override")
+		}
+		return nil
+	})
+}
+
+// GetCommandRoles returns the roles allowed to run a command in a guild through overrides.
+func (r *Repository) GetCommandRoles(guildID, command string) ([]string, error) {
+	var roleIDs []string
+	err := WithRetry(func() error {
+		return r.db.Model(&models.CommandPermission{}).
+			Where("guild_id = ? AND command = ?", guildID, command).
+			Pluck("role_id", &roleIDs).Error
+	})
+	return roleIDs, err
+}
+
+// GetCommandPermissions returns all command overrides of a guild.
+func (r *Repository) GetCommandPermissions(guildID string) ([]models.CommandPermission, error) {
+	var permissions []models.CommandPermission
+	err := WithRetry(func() error {
+		return r.db.Where("guild_id = ?", guildID).Order("command").Find(&permissions).Error
+	})
+	return permissions, err
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+)
+
+func (r *Repository) AddHeldNotification(held *models.HeldNotification) error {
+	return WithRetry(func() error {
+		return r.db.Create(held).Error
+	})
+}
+
+// GetHeldNotifications returns a guild's held notifications, oldest first.
+func (r *Repository) GetHeldNotifications(guildID string) ([]models.HeldNotification, error) {
+	var held []models.HeldNotification
+	err := WithRetry(func() error {
+		return r.db.Where("guild_id = ?", guildID).Order("id").Find(&held).Error
+	})
+	return held, err
+}
+
+// GetGuildsWithHeldNotifications returns the IDs of guilds that have notifications waiting.
+func (r *Repository) GetGuildsWithHeldNotifications() ([]string, error) {
+	var guildIDs []string
+	err := WithRetry(func() error {
+		return r.db.Model(&models.HeldNotification{}).Distinct().Pluck("guild_id", &guildIDs).Error
+	})
+	return guildIDs, err
+}
+
+func (r *Repository) DeleteHeldNotifications(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return WithRetry(func() error {
+		return r.db.Delete(&models.HeldNotification{}, ids).Error
+	})
+}
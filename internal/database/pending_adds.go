@@ -0,0 +1,48 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+	"gorm.io/gorm"
+)
+
+// CreatePendingAdd stores a pending live-only confirmation and removes expired ones.
+func (r *Repository) CreatePendingAdd(pending *models.PendingAdd) error {
+	return WithRetry(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("expires_at < ?", time.Now().Unix()).Delete(&models.PendingAdd{}).Error; err != nil {
+				return err
+			}
+			return tx.Create(pending).Error
+		})
+	})
+}
+
+// GetPendingAdd returns a pending confirmation, or nil if it does not exist or has expired.
+func (r *Repository) GetPendingAdd(id string) (*models.PendingAdd, error) {
+	var pending models.PendingAdd
+	err := WithRetry(func() error {
+		return r.db.Where("id = ? AND expires_at >= ?", id, time.Now().Unix()).First(&pending).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
+
+// DeletePendingAdd removes a pending confirmation. It reports whether the confirmation still
+// existed, so that only one instance acts on a click.
+func (r *Repository) DeletePendingAdd(id string) (bool, error) {
+	var deleted bool
+	err := WithRetry(func() error {
+		result := r.db.Where("id = ?", id).Delete(&models.PendingAdd{})
+		deleted = result.RowsAffected > 0
+		return result.Error
+	})
+	return deleted, err
+}
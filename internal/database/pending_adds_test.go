@@ -0,0 +1,84 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NotiFansly/notifansly-bot/internal/models"
+)
+
+// newTestRepository returns a repository backed by a fresh SQLite database.
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+	if err := Init("sqlite", filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("could not initialize the database: %v", err)
+	}
+	t.Cleanup(Close)
+	return NewRepository()
+}
+
+func TestPendingAddExpiry(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		wantFound bool
+	}{
+		{name: "open confirmation", expiresAt: now.Add(15 * time.Minute), wantFound: true},
+		{name: "expires this minute", expiresAt: now.Add(time.Minute), wantFound: true},
+		{name: "expired confirmation", expiresAt: now.Add(-time.Minute), wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			if err := repo.db.Create(&models.PendingAdd{ID: "pending", ExpiresAt: tt.expiresAt.Unix()}).Error; err != nil {
+				t.Fatal(err)
+			}
+
+			pending, err := repo.GetPendingAdd("pending")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found := pending != nil; found != tt.wantFound {
+				t.Errorf("found = %v, want %v", found, tt.wantFound)
+			}
+		})
+	}
+
+	t.Run("storing a confirmation removes expired ones", func(t *testing.T) {
+		repo := newTestRepository(t)
+		if err := repo.db.Create(&models.PendingAdd{ID: "expired", ExpiresAt: now.Add(-time.Minute).Unix()}).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.CreatePendingAdd(&models.PendingAdd{ID: "open", ExpiresAt: now.Add(15 * time.Minute).Unix()}); err != nil {
+			t.Fatal(err)
+		}
+
+		var ids []string
+		if err := repo.db.Model(&models.PendingAdd{}).Pluck("id", &ids).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 1 || ids[0] != "open" {
+			t.Errorf("stored confirmations = %v, want [open]", ids)
+		}
+	})
+
+	t.Run("only the first answer deletes the confirmation", func(t *testing.T) {
+		repo := newTestRepository(t)
+		if err := repo.CreatePendingAdd(&models.PendingAdd{ID: "pending", ExpiresAt: now.Add(15 * time.Minute).Unix()}); err != nil {
+			t.Fatal(err)
+		}
+		for idx, want := range []bool{true, false} {
+			deleted, err := repo.DeletePendingAdd("pending")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deleted != want {
+				t.Errorf("answer %d deleted = %v, want %v", idx+1, deleted, want)
+			}
+		}
+	})
+}
@@ -205,9 +205,12 @@ func (r *Repository) GetMonitoredUser(guildID, userID string) (*models.Monitored
 }
 
 func (r *Repository) GetMonitoredUserByUsername(guildID, username string) (*models.MonitoredUser, error) {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return nil, err
+	}
 	var user models.MonitoredUser
-	err := WithRetry(func() error {
+	err = WithRetry(func() error {
 		result := r.db.Where("guild_id = ? AND LOWER(username) = ?", guildID, username).First(&user)
 		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return result.Error
@@ -233,7 +236,7 @@ func (r *Repository) AddOrUpdateMonitoredUser(user *models.MonitoredUser) error
 		return r.db.Clauses(clause.OnConflict{
 			Columns: []clause.Column{{Name: "guild_id"}, {Name: "user_id"}},
 			DoUpdates: clause.AssignmentColumns([]string{
-				"username", "notification_channel", "post_notification_channel", "live_notification_channel",
+				"username", "display_name", "notification_channel", "post_notification_channel", "live_notification_channel",
 				"last_post_id", "last_stream_start", "mention_role", "avatar_location",
 				"avatar_location_updated_at", "live_image_url", "posts_enabled", "live_enabled",
 				"live_mention_role", "post_mention_role",
@@ -258,7 +261,10 @@ func (r *Repository) DeleteMonitoredUser(guildID, userID string) error {
 
 // MODIFIED: DeleteMonitoredUserByUsername to also clean up formats
 func (r *Repository) DeleteMonitoredUserByUsername(guildID, username string) error {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		// First, find the user to get their ID for deleting related data
 		var user models.MonitoredUser
@@ -279,6 +285,18 @@ func (r *Repository) DeleteMonitoredUserByUsername(guildID, username string) err
 			return err
 		}
 
+		// Drop posts queued for the next digest, the burst a new post would be collapsed into and
+		// notifications held for quiet hours, so none of them is sent for a removed creator
+		if err := tx.Where("guild_id = ? AND creator_id = ?", guildID, user.UserID).Delete(&models.DigestItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("guild_id = ? AND user_id = ?", guildID, user.UserID).Delete(&models.PostBurst{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("guild_id = ? AND creator_id = ?", guildID, user.UserID).Delete(&models.HeldNotification{}).Error; err != nil {
+			return err
+		}
+
 		// Finally, delete the monitored user
 		if err := tx.Where("guild_id = ? AND user_id = ?", guildID, user.UserID).Delete(&models.MonitoredUser{}).Error; err != nil {
 			return err
@@ -351,8 +369,86 @@ func (r *Repository) UpdateAvatarInfo(guildID, userID, avatarLocation string) er
 	})
 }
 
-func (r *Repository) UpdateLastPostIDByUsername(guildID, username, postID string) error {
+// UpdateCreatorAccountInfo updates the avatar and display name of a creator in every guild monitoring them.
+func (r *Repository) UpdateCreatorAccountInfo(userID, avatarLocation, displayName string) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.MonitoredUser{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]any{
+				"avatar_location":            avatarLocation,
+				"avatar_location_updated_at": time.Now().Unix(),
+				"display_name":               displayName,
+			}).Error
+	})
+}
+
+// RenameCreator sets a new username for a creator in every guild monitoring them and
+// keeps the old username as an alias so it still resolves in commands.
+func (r *Repository) RenameCreator(userID, oldUsername, newUsername string) error {
+	return WithRetry(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.MonitoredUser{}).Where("user_id = ?", userID).Update("username", newUsername).Error; err != nil {
+				return err
+			}
+
+			alias := &models.UsernameAlias{UserID: userID, Username: strings.ToLower(oldUsername), ChangedAt: time.Now().Unix()}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "username"}},
+				DoUpdates: clause.AssignmentColumns([]string{"changed_at"}),
+			}).Create(alias).Error; err != nil {
+				return err
+			}
+
+			// A creator switching back to a previous name should not keep it as an alias.
+			return tx.Where("user_id = ? AND username = ?", userID, strings.ToLower(newUsername)).Delete(&models.UsernameAlias{}).Error
+		})
+	})
+}
+
+// GetUsernameAliases returns the previous usernames of a creator, most recent first.
+func (r *Repository) GetUsernameAliases(userID string) ([]models.UsernameAlias, error) {
+	var aliases []models.UsernameAlias
+	err := WithRetry(func() error {
+		return r.db.Where("user_id = ?", userID).Order("changed_at DESC").Find(&aliases).Error
+	})
+	return aliases, err
+}
+
+// resolveUsername returns the lowercase current username for username in a guild. If no
+// monitored creator currently uses that name, previous usernames are checked as well.
+func (r *Repository) resolveUsername(guildID, username string) (string, error) {
 	username = strings.ToLower(username)
+
+	var count int64
+	err := WithRetry(func() error {
+		return r.db.Model(&models.MonitoredUser{}).Where("guild_id = ? AND LOWER(username) = ?", guildID, username).Count(&count).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return username, nil
+	}
+
+	var users []models.MonitoredUser
+	err = WithRetry(func() error {
+		aliasedIDs := r.db.Model(&models.UsernameAlias{}).Select("user_id").Where("username = ?", username)
+		return r.db.Where("guild_id = ? AND user_id IN (?)", guildID, aliasedIDs).Limit(1).Find(&users).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(users) == 0 {
+		return username, nil
+	}
+	return strings.ToLower(users[0].Username), nil
+}
+
+func (r *Repository) UpdateLastPostIDByUsername(guildID, username, postID string) error {
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
@@ -368,7 +464,10 @@ func (r *Repository) UpdateLastPostIDByUsername(guildID, username, postID string
 }
 
 func (r *Repository) UpdateAvatarInfoByUsername(guildID, username, avatarLocation string) error {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
@@ -387,7 +486,10 @@ func (r *Repository) UpdateAvatarInfoByUsername(guildID, username, avatarLocatio
 }
 
 func (r *Repository) DisablePostsByUsername(guildID, username string) error {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
@@ -403,7 +505,10 @@ func (r *Repository) DisablePostsByUsername(guildID, username string) error {
 }
 
 func (r *Repository) EnablePostsByUsername(guildID, username string) error {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
@@ -419,7 +524,10 @@ func (r *Repository) EnablePostsByUsername(guildID, username string) error {
 }
 
 func (r *Repository) DisableLiveByUsername(guildID, username string) error {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
@@ -435,7 +543,10 @@ func (r *Repository) DisableLiveByUsername(guildID, username string) error {
 }
 
 func (r *Repository) EnableLiveByUsername(guildID, username string) error {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
@@ -450,6 +561,143 @@ func (r *Repository) EnableLiveByUsername(guildID, username string) error {
 	})
 }
 
+func (r *Repository) SetProfileEnabledByUsername(guildID, username string, enabled bool) error {
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
+	return WithRetry(func() error {
+		result := r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
+			Update("profile_enabled", enabled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("user not found")
+		}
+		return nil
+	})
+}
+
+// SetPostDeliveryByUsername sets whether a creator's posts are sent immediately or in the digest.
+// An empty delivery follows the guild's digest setting.
+func (r *Repository) SetPostDeliveryByUsername(guildID, username, delivery string) error {
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
+	return WithRetry(func() error {
+		result := r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
+			Update("post_delivery", delivery)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("user not found")
+		}
+		return nil
+	})
+}
+
+// SetNotificationCooldownsByUsername sets a creator's mention cooldown and post burst window, in minutes.
+func (r *Repository) SetNotificationCooldownsByUsername(guildID, username string, mentionCooldown, burstWindow int) error {
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
+	return WithRetry(func() error {
+		result := r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
+			Updates(map[string]any{
+				"mention_cooldown_minutes": mentionCooldown,
+				"burst_window_minutes":     burstWindow,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("user not found")
+		}
+		return nil
+	})
+}
+
+// UpdateThreadIDs stores the forum threads a creator's notifications are posted in.
+func (r *Repository) UpdateThreadIDs(guildID, userID, postThreadID, liveThreadID string) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND user_id = ?", guildID, userID).
+			Updates(map[string]any{
+				"post_thread_id": postThreadID,
+				"live_thread_id": liveThreadID,
+			}).Error
+	})
+}
+
+func (r *Repository) UpdateLiveEventID(guildID, userID, eventID string) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND user_id = ?", guildID, userID).
+			Update("live_event_id", eventID).Error
+	})
+}
+
+func (r *Repository) UpdateLastMentionAt(guildID, userID string, timestamp int64) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND user_id = ?", guildID, userID).
+			Update("last_mention_at", timestamp).Error
+	})
+}
+
+// GetPostBurst returns the current post burst of a creator in a guild, or (nil, nil) if there is none.
+func (r *Repository) GetPostBurst(guildID, userID string) (*models.PostBurst, error) {
+	var bursts []models.PostBurst
+	err := WithRetry(func() error {
+		return r.db.Where("guild_id = ? AND user_id = ?", guildID, userID).Limit(1).Find(&bursts).Error
+	})
+	if err != nil || len(bursts) == 0 {
+		return nil, err
+	}
+	return &bursts[0], nil
+}
+
+func (r *Repository) SavePostBurst(burst *models.PostBurst) error {
+	return WithRetry(func() error {
+		return r.db.Save(burst).Error
+	})
+}
+
+// GetProfileSnapshot returns the last stored profile of a creator, or (nil, nil) if none exists yet.
+func (r *Repository) GetProfileSnapshot(userID string) (*models.CreatorProfileSnapshot, error) {
+	var snapshots []models.CreatorProfileSnapshot
+	err := WithRetry(func() error {
+		return r.db.Where("user_id = ?", userID).Limit(1).Find(&snapshots).Error
+	})
+	if err != nil || len(snapshots) == 0 {
+		return nil, err
+	}
+	return &snapshots[0], nil
+}
+
+func (r *Repository) SaveProfileSnapshot(snapshot *models.CreatorProfileSnapshot) error {
+	return WithRetry(func() error {
+		return r.db.Save(snapshot).Error
+	})
+}
+
+// SetSubscriberRole stores the self-service notification role of a creator in a guild.
+// An empty roleID disables self-service subscriptions.
+func (r *Repository) SetSubscriberRole(guildID, userID, roleID string) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND user_id = ?", guildID, userID).
+			Update("subscriber_role_id", roleID).Error
+	})
+}
+
 func (r *Repository) CountMonitoredUsers() (int64, error) {
 	var count int64
 	err := WithRetry(func() error {
@@ -468,12 +716,24 @@ func (r *Repository) CountGuilds() (int64, error) {
 
 func (r *Repository) DeleteAllUsersInGuild(guildID string) error {
 	return WithRetry(func() error {
+		if err := r.db.Delete(&models.HeldNotification{}, "guild_id = ?", guildID).Error; err != nil {
+			return err
+		}
+		if err := r.db.Delete(&models.DigestItem{}, "guild_id = ?", guildID).Error; err != nil {
+			return err
+		}
+		if err := r.db.Delete(&models.PostBurst{}, "guild_id = ?", guildID).Error; err != nil {
+			return err
+		}
 		return r.db.Delete(&models.MonitoredUser{}, "guild_id = ?", guildID).Error
 	})
 }
 
 func (r *Repository) UpdateLiveImageURL(guildID, username, imageURL string) error {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
@@ -489,7 +749,10 @@ func (r *Repository) UpdateLiveImageURL(guildID, username, imageURL string) erro
 }
 
 func (r *Repository) UpdatePostChannel(guildID, username, channelID string) error {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
@@ -505,7 +768,10 @@ func (r *Repository) UpdatePostChannel(guildID, username, channelID string) erro
 }
 
 func (r *Repository) UpdateLiveChannel(guildID, username, channelID string) error {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
@@ -521,7 +787,10 @@ func (r *Repository) UpdateLiveChannel(guildID, username, channelID string) erro
 }
 
 func (r *Repository) UpdatePostMentionRole(guildID, username, roleID string) error {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
@@ -537,7 +806,10 @@ func (r *Repository) UpdatePostMentionRole(guildID, username, roleID string) err
 }
 
 func (r *Repository) UpdateLiveMentionRole(guildID, username, roleID string) error {
-	username = strings.ToLower(username)
+	username, err := r.resolveUsername(guildID, username)
+	if err != nil {
+		return err
+	}
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND LOWER(username) = ?", guildID, username).
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	//"log"
 	"time"
+	"unicode/utf8"
 
 	"github.com/NotiFansly/notifansly-bot/api"
+	"github.com/NotiFansly/notifansly-bot/internal/models"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -39,7 +41,7 @@ func CreateLiveStreamEmbed(username string, streamInfo *api.StreamResponse, avat
 			},
 			{
 				Name:   "Started At",
-				Value:  time.Unix(streamInfo.Response.Stream.StartedAt/1000, 0).Format(time.RFC1123),
+				Value:  discordTimestamp(streamInfo.Response.Stream.StartedAt / 1000),
 				Inline: true,
 			},
 		},
@@ -91,3 +93,359 @@ func CreatePostEmbed(username string, post api.Post, avatarLocation string, post
 
 	return embed
 }
+
+// CollapsePostBurst turns the embed of a creator's latest post into a summary of a burst of
+// posts. postIDs lists every post of the burst, newest first.
+func CollapsePostBurst(embed *discordgo.MessageEmbed, username string, postIDs []string) *discordgo.MessageEmbed {
+	embed.Title = fmt.Sprintf("%d new posts from %s", len(postIDs), username)
+
+	var links string
+	for idx, postID := range postIDs {
+		line := fmt.Sprintf("[Post %d](https://fansly.com/post/%s)\n", len(postIDs)-idx, postID)
+		if len(links)+len(line) > 1000 {
+			links += fmt.Sprintf("…and %d more", len(postIDs)-idx)
+			break
+		}
+		links += line
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  "Posts",
+		Value: links,
+	})
+	return embed
+}
+
+func CreateRenameEmbed(oldUsername, newUsername, avatarLocation string) *discordgo.MessageEmbed {
+	creatorUrl := fmt.Sprintf("https://fansly.com/%s", newUsername)
+
+	return &discordgo.MessageEmbed{
+		Title:       "Creator Renamed",
+		URL:         creatorUrl,
+		Color:       0x03b2f8,
+		Description: fmt.Sprintf("**%s** is now known as **%s** on Fansly.", oldUsername, newUsername),
+		Author: &discordgo.MessageEmbedAuthor{
+			URL:     creatorUrl,
+			Name:    newUsername,
+			IconURL: avatarLocation,
+		},
+		Thumbnail: &discordgo.MessageEmbedThumbnail{
+			URL: avatarLocation,
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// ProfileChange describes a single changed field of a creator's profile.
+type ProfileChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+func CreateProfileChangeEmbed(username string, changes []ProfileChange, avatarLocation, bannerLocation string) *discordgo.MessageEmbed {
+	creatorUrl := fmt.Sprintf("https://fansly.com/%s", username)
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Profile Updated",
+		URL:         creatorUrl,
+		Color:       0x03b2f8,
+		Description: fmt.Sprintf("%s updated their Fansly profile.", username),
+		Author: &discordgo.MessageEmbedAuthor{
+			URL:     creatorUrl,
+			Name:    username,
+			IconURL: avatarLocation,
+		},
+		Thumbnail: &discordgo.MessageEmbedThumbnail{
+			URL: avatarLocation,
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	for _, change := range changes {
+		var value string
+		switch change.Field {
+		case "Avatar":
+			value = "New avatar uploaded"
+		case "Banner":
+			value = "New banner uploaded"
+			if bannerLocation != "" {
+				embed.Image = &discordgo.MessageEmbedImage{URL: bannerLocation}
+			}
+		case "Bio":
+			value = Truncate(change.New, 1024)
+			if value == "" {
+				value = "*Bio removed*"
+			}
+		default:
+			value = fmt.Sprintf("%s → %s", orNone(change.Old), orNone(change.New))
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  change.Field,
+			Value: value,
+		})
+	}
+
+	return embed
+}
+
+// discordTimestamp renders a Unix time with Discord's timestamp markup, which every
+// reader sees in their own timezone.
+func discordTimestamp(unix int64) string {
+	return fmt.Sprintf("<t:%d:f> (<t:%d:R>)", unix, unix)
+}
+
+// Truncate shortens text to at most limit characters, marking the cut with an ellipsis.
+func Truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "*none*"
+	}
+	return value
+}
+
+func CreateAuditEmbed(entry models.AuditLogEntry) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "Configuration Changed",
+		Color:       0x99aab5,
+		Description: fmt.Sprintf("<@%s> used `/%s`", entry.ActorID, entry.Action),
+		Timestamp:   time.Unix(entry.CreatedAt, 0).Format(time.RFC3339),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%s (%s)", entry.ActorName, entry.ActorID),
+		},
+	}
+
+	if entry.Target != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Target", Value: entry.Target})
+	}
+	embed.Fields = append(embed.Fields,
+		&discordgo.MessageEmbedField{Name: "Old Value", Value: Truncate(orNone(entry.OldValue), 1024), Inline: true},
+		&discordgo.MessageEmbedField{Name: "New Value", Value: Truncate(orNone(entry.NewValue), 1024), Inline: true},
+	)
+
+	return embed
+}
+
+// MaxMessageEmbedLength is the most characters Discord accepts across all embeds of one message.
+const MaxMessageEmbedLength = 6000
+
+// Length counts the characters of an embed that Discord adds up against MaxMessageEmbedLength.
+func Length(embed *discordgo.MessageEmbed) int {
+	length := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	for _, field := range embed.Fields {
+		length += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	if embed.Footer != nil {
+		length += utf8.RuneCountInString(embed.Footer.Text)
+	}
+	if embed.Author != nil {
+		length += utf8.RuneCountInString(embed.Author.Name)
+	}
+	return length
+}
+
+// DigestPage is one message of a digest and the posts it lists.
+type DigestPage struct {
+	Embed *discordgo.MessageEmbed
+	Items []models.DigestItem
+}
+
+// CreateDigestEmbeds summarizes the posts collected for a digest, with one field per creator.
+// The digest is split into pages that each fit in one message.
+func CreateDigestEmbeds(title string, items []models.DigestItem, color int) []DigestPage {
+	embedColor := 0x03b2f8
+	if color != 0 {
+		embedColor = color
+	}
+
+	var creatorOrder []string
+	byCreator := make(map[string][]models.DigestItem)
+	for _, item := range items {
+		if _, ok := byCreator[item.CreatorID]; !ok {
+			creatorOrder = append(creatorOrder, item.CreatorID)
+		}
+		byCreator[item.CreatorID] = append(byCreator[item.CreatorID], item)
+	}
+	description := fmt.Sprintf("%d new post(s) from %d creator(s).", len(items), len(creatorOrder))
+
+	const (
+		maxFields     = 25
+		maxFieldValue = 1000
+		// pageLength leaves room for the page number added to the title.
+		pageLength = MaxMessageEmbedLength - 100
+	)
+
+	var pages []DigestPage
+	page := DigestPage{}
+	newPage := func() {
+		if page.Embed != nil {
+			pages = append(pages, page)
+		}
+		page = DigestPage{Embed: &discordgo.MessageEmbed{
+			Title:       title,
+			Color:       embedColor,
+			Description: description,
+			Timestamp:   time.Now().Format(time.RFC3339),
+		}}
+	}
+	addField := func(name, value string, fieldItems []models.DigestItem) {
+		size := utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+		if len(page.Embed.Fields) == maxFields || Length(page.Embed)+size > pageLength {
+			newPage()
+		}
+		page.Embed.Fields = append(page.Embed.Fields, &discordgo.MessageEmbedField{Name: name, Value: value})
+		page.Items = append(page.Items, fieldItems...)
+	}
+	newPage()
+
+	for _, creatorID := range creatorOrder {
+		posts := byCreator[creatorID]
+		name := fmt.Sprintf("%s (%d)", posts[0].Username, len(posts))
+
+		// A creator with many posts continues in further fields.
+		var lines string
+		var fieldItems []models.DigestItem
+		for _, post := range posts {
+			excerpt := post.Excerpt
+			if excerpt == "" {
+				excerpt = "View post"
+			}
+			line := fmt.Sprintf("• [%s](https://fansly.com/post/%s)\n", excerpt, post.PostID)
+			if len(fieldItems) > 0 && utf8.RuneCountInString(lines+line) > maxFieldValue {
+				addField(name, lines, fieldItems)
+				name = fmt.Sprintf("%s (continued)", posts[0].Username)
+				lines, fieldItems = "", nil
+			}
+			lines += line
+			fieldItems = append(fieldItems, post)
+		}
+		addField(name, lines, fieldItems)
+	}
+	pages = append(pages, page)
+
+	if len(pages) > 1 {
+		for idx := range pages {
+			pages[idx].Embed.Title = fmt.Sprintf("%s (%d/%d)", title, idx+1, len(pages))
+		}
+	}
+	return pages
+}
+
+// LiveCreator is a creator shown on a status board.
+type LiveCreator struct {
+	Username    string
+	ViewerCount int
+	StartedAt   int64 // Unix seconds
+}
+
+// CreateStatusBoardEmbed lists the creators that are live right now.
+func CreateStatusBoardEmbed(live []LiveCreator) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     fmt.Sprintf("🔴 Live Now (%d)", len(live)),
+		Color:     0xe74c3c,
+		Footer:    &discordgo.MessageEmbedFooter{Text: "Last updated"},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if len(live) == 0 {
+		embed.Title = "Live Now"
+		embed.Color = 0x99aab5
+		embed.Description = "Nobody is live right now."
+		return embed
+	}
+
+	var description string
+	for idx, creator := range live {
+		line := fmt.Sprintf("**[%s](https://fansly.com/live/%s)** · 👁 %d · live for %s (since <t:%d:t>)\n",
+			creator.Username, creator.Username, creator.ViewerCount, liveDuration(creator.StartedAt), creator.StartedAt)
+		if len(description)+len(line) > 4000 {
+			description += fmt.Sprintf("…and %d more", len(live)-idx)
+			break
+		}
+		description += line
+	}
+	embed.Description = description
+	return embed
+}
+
+func liveDuration(startedAt int64) string {
+	return formatDuration(time.Since(time.Unix(startedAt, 0)))
+}
+
+func formatDuration(elapsed time.Duration) string {
+	elapsed = elapsed.Round(time.Minute)
+	if elapsed < time.Minute {
+		return "less than a minute"
+	}
+	hours := int(elapsed.Hours())
+	minutes := int(elapsed.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+// CreatorStats summarizes a creator's recorded activity over a period.
+type CreatorStats struct {
+	Username     string
+	Period       string
+	Since        int64 // Unix seconds of the oldest recorded activity in the period, 0 if none
+	Posts        int
+	PostsPerWeek float64
+	// TopHours are the hours of the day posts were most often made, in Timezone, busiest first.
+	TopHours        []int
+	Timezone        string
+	Streams         int
+	AverageDuration time.Duration
+	AverageViewers  int
+	PeakViewers     int
+}
+
+// CreateCreatorStatsEmbed shows a creator's posting and streaming statistics.
+func CreateCreatorStatsEmbed(stats CreatorStats) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     fmt.Sprintf("📊 %s · %s", stats.Username, stats.Period),
+		URL:       fmt.Sprintf("https://fansly.com/%s", stats.Username),
+		Color:     0x03b2f8,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if stats.Since != 0 {
+		embed.Description = fmt.Sprintf("Based on activity recorded since <t:%d:D>.", stats.Since)
+	} else {
+		embed.Description = "No activity has been recorded for this period yet."
+	}
+
+	hours := "—"
+	if len(stats.TopHours) > 0 {
+		hours = ""
+		for idx, hour := range stats.TopHours {
+			if idx > 0 {
+				hours += ", "
+			}
+			hours += fmt.Sprintf("%02d:00–%02d:00", hour, (hour+1)%24)
+		}
+		hours += fmt.Sprintf(" (%s)", stats.Timezone)
+	}
+
+	duration, viewers := "—", "—"
+	if stats.Streams > 0 {
+		duration = formatDuration(stats.AverageDuration)
+		viewers = fmt.Sprintf("%d average · %d peak", stats.AverageViewers, stats.PeakViewers)
+	}
+
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{Name: "Posts", Value: fmt.Sprintf("%d (%.1f per week)", stats.Posts, stats.PostsPerWeek), Inline: true},
+		{Name: "Typical Posting Hours", Value: hours, Inline: true},
+		{Name: "\u200b", Value: "\u200b", Inline: true},
+		{Name: "Streams", Value: fmt.Sprintf("%d", stats.Streams), Inline: true},
+		{Name: "Average Duration", Value: duration, Inline: true},
+		{Name: "Viewers", Value: viewers, Inline: true},
+	}
+	return embed
+}
@@ -0,0 +1,61 @@
+package embed
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCollapsePostBurst(t *testing.T) {
+	postIDs := func(count int) []string {
+		ids := make([]string, count)
+		for idx := range ids {
+			ids[idx] = fmt.Sprintf("%018d", count-idx)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name      string
+		postIDs   []string
+		wantTitle string
+		wantLinks int
+		wantMore  string
+	}{
+		{name: "two posts", postIDs: postIDs(2), wantTitle: "2 new posts from alice", wantLinks: 2},
+		{name: "fits the field", postIDs: postIDs(15), wantTitle: "15 new posts from alice", wantLinks: 15},
+		{name: "overflowing links", postIDs: postIDs(40), wantTitle: "40 new posts from alice", wantLinks: 18, wantMore: "…and 22 more"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CollapsePostBurst(&discordgo.MessageEmbed{Title: "New post from alice"}, "alice", tt.postIDs)
+			if got.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			if len(got.Fields) != 1 {
+				t.Fatalf("got %d fields, want 1", len(got.Fields))
+			}
+
+			value := got.Fields[0].Value
+			if len(value) > 1024 {
+				t.Errorf("field value is %d characters long, Discord allows 1024", len(value))
+			}
+			if links := strings.Count(value, "https://fansly.com/post/"); links != tt.wantLinks {
+				t.Errorf("field has %d links, want %d", links, tt.wantLinks)
+			}
+			first := fmt.Sprintf("[Post %d](https://fansly.com/post/%s)", len(tt.postIDs), tt.postIDs[0])
+			if !strings.HasPrefix(value, first) {
+				t.Errorf("field does not start with the newest post %s:\n%s", first, value)
+			}
+			if tt.wantMore != "" && !strings.HasSuffix(value, tt.wantMore) {
+				t.Errorf("field does not end with %q:\n%s", tt.wantMore, value)
+			}
+			if tt.wantMore == "" && strings.Contains(value, "more") {
+				t.Errorf("field mentions more posts although every post is listed:\n%s", value)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+// UsernameAlias records a username a creator used before renaming, so old names keep
+// resolving in commands.
+type UsernameAlias struct {
+	UserID    string `gorm:"primaryKey;column:user_id"`
+	Username  string `gorm:"primaryKey;column:username"` // stored lowercase
+	ChangedAt int64  `gorm:"column:changed_at"`
+}
+
+func (UsernameAlias) TableName() string {
+	return "username_aliases"
+}
+
+// CreatorProfileSnapshot is the last seen public profile of a creator, used to detect
+// avatar, banner, display name and bio changes.
+type CreatorProfileSnapshot struct {
+	UserID      string `gorm:"primaryKey;column:user_id"`
+	DisplayName string `gorm:"column:display_name"`
+	About       string `gorm:"column:about"`
+	AvatarID    string `gorm:"column:avatar_id"`
+	BannerID    string `gorm:"column:banner_id"`
+	UpdatedAt   int64  `gorm:"column:updated_at"`
+}
+
+func (CreatorProfileSnapshot) TableName() string {
+	return "creator_profile_snapshots"
+}
+
+// StreamSession is one live stream of a creator. EndedAt is zero while the stream is live.
+type StreamSession struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement;column:id"`
+	UserID      string `gorm:"column:user_id;uniqueIndex:idx_stream_sessions_user_start"`
+	StartedAt   int64  `gorm:"column:started_at;uniqueIndex:idx_stream_sessions_user_start"`
+	EndedAt     int64  `gorm:"column:ended_at"`
+	PeakViewers int    `gorm:"column:peak_viewers"`
+	// ViewerTotal and ViewerSamples add up the viewer counts seen each monitoring cycle, for the average.
+	ViewerTotal   int64 `gorm:"column:viewer_total"`
+	ViewerSamples int   `gorm:"column:viewer_samples"`
+}
+
+func (StreamSession) TableName() string {
+	return "stream_sessions"
+}
+
+// AverageViewers returns the mean viewer count over the monitoring cycles that saw the stream.
+func (s StreamSession) AverageViewers() int {
+	if s.ViewerSamples == 0 {
+		return 0
+	}
+	return int(s.ViewerTotal / int64(s.ViewerSamples))
+}
+
+// PostEvent records when a creator published a post.
+type PostEvent struct {
+	UserID   string `gorm:"primaryKey;column:user_id"`
+	PostID   string `gorm:"primaryKey;column:post_id"`
+	PostedAt int64  `gorm:"column:posted_at;index"`
+}
+
+func (PostEvent) TableName() string {
+	return "post_events"
+}
+
+// StreamViewerSample is the viewer count of a stream seen by one monitoring cycle.
+type StreamViewerSample struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement;column:id"`
+	UserID    string `gorm:"column:user_id;index:idx_stream_viewer_samples_stream"`
+	StartedAt int64  `gorm:"column:started_at;index:idx_stream_viewer_samples_stream"`
+	SampledAt int64  `gorm:"column:sampled_at;index"`
+	Viewers   int    `gorm:"column:viewers"`
+}
+
+func (StreamViewerSample) TableName() string {
+	return "stream_viewer_samples"
+}
@@ -0,0 +1,111 @@
+package models
+
+// GuildSettings holds per-guild configuration that is not tied to a single creator.
+type GuildSettings struct {
+	GuildID         string `gorm:"primaryKey;column:guild_id"`
+	AnnounceRenames bool   `gorm:"column:announce_renames"`
+	AuditChannelID  string `gorm:"column:audit_channel_id"`
+	// Timezone is an IANA zone name such as "Europe/Berlin"; empty means UTC.
+	Timezone string `gorm:"column:timezone"`
+	// QuietHoursStart and QuietHoursEnd are "HH:MM" in the guild's timezone; empty disables quiet hours.
+	QuietHoursStart string `gorm:"column:quiet_hours_start"`
+	QuietHoursEnd   string `gorm:"column:quiet_hours_end"`
+	// QuietHoursMode is "mute" (post without pinging) or "hold" (deliver when quiet hours end).
+	QuietHoursMode string `gorm:"column:quiet_hours_mode"`
+	// DigestSchedule is "daily" or "weekly"; empty disables digests.
+	DigestSchedule string `gorm:"column:digest_schedule"`
+	// DigestTime is "HH:MM" in the guild's timezone and DigestWeekday the day of weekly digests (0 = Sunday).
+	DigestTime    string `gorm:"column:digest_time"`
+	DigestWeekday int    `gorm:"column:digest_weekday"`
+	// DigestAllCreators sends every creator's posts to the digest unless a creator opts out.
+	DigestAllCreators bool  `gorm:"column:digest_all_creators"`
+	LastDigestAt      int64 `gorm:"column:last_digest_at"`
+	// AutoPublish crossposts notifications sent to announcement channels to following servers.
+	AutoPublish bool `gorm:"column:auto_publish"`
+	// LiveEvents mirrors live streams as scheduled events in the guild's Events tab.
+	LiveEvents bool `gorm:"column:live_events"`
+	// StatusBoardChannelID and StatusBoardMessageID locate the message listing who is live right now.
+	StatusBoardChannelID string `gorm:"column:status_board_channel_id"`
+	StatusBoardMessageID string `gorm:"column:status_board_message_id"`
+	// LiveCounterChannelID is renamed to show how many creators are live.
+	LiveCounterChannelID string `gorm:"column:live_counter_channel_id"`
+	// CalendarToken is the secret in the URLs of the guild's calendar feeds; empty until a feed is requested.
+	CalendarToken string `gorm:"column:calendar_token;index"`
+}
+
+func (GuildSettings) TableName() string {
+	return "guild_settings"
+}
+
+// GuildManagerRole lets members of a role run the bot's management commands in a guild
+// without needing Administrator or Manage Server.
+type GuildManagerRole struct {
+	GuildID string `gorm:"primaryKey;column:guild_id"`
+	RoleID  string `gorm:"primaryKey;column:role_id"`
+}
+
+func (GuildManagerRole) TableName() string {
+	return "guild_manager_roles"
+}
+
+// CommandPermission allows a role to run a specific command. When a command has any
+// overrides in a guild, they replace the manager roles for that command.
+type CommandPermission struct {
+	GuildID string `gorm:"primaryKey;column:guild_id"`
+	Command string `gorm:"primaryKey;column:command"`
+	RoleID  string `gorm:"primaryKey;column:role_id"`
+}
+
+func (CommandPermission) TableName() string {
+	return "command_permissions"
+}
+
+// AuditLogEntry records a configuration change made in a guild.
+type AuditLogEntry struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement;column:id"`
+	GuildID   string `gorm:"column:guild_id;index"`
+	ActorID   string `gorm:"column:actor_id"`
+	ActorName string `gorm:"column:actor_name"`
+	Action    string `gorm:"column:action"`
+	Target    string `gorm:"column:target"`
+	OldValue  string `gorm:"column:old_value"`
+	NewValue  string `gorm:"column:new_value"`
+	CreatedAt int64  `gorm:"column:created_at"`
+}
+
+func (AuditLogEntry) TableName() string {
+	return "audit_log_entries"
+}
+
+// HeldNotification is a notification kept back during a guild's quiet hours.
+type HeldNotification struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement;column:id"`
+	GuildID   string `gorm:"column:guild_id;index"`
+	CreatorID string `gorm:"column:creator_id;index"`
+	ChannelID string `gorm:"column:channel_id"`
+	Content   string `gorm:"column:content"`
+	EmbedJSON string `gorm:"column:embed_json"`
+	// SuppressMentions is set when the creator's mention cooldown was active, so the release must not ping for it.
+	SuppressMentions bool  `gorm:"column:suppress_mentions"`
+	CreatedAt        int64 `gorm:"column:created_at"`
+}
+
+func (HeldNotification) TableName() string {
+	return "held_notifications"
+}
+
+// DigestItem is a post waiting to be included in a guild's next digest.
+type DigestItem struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement;column:id"`
+	GuildID   string `gorm:"column:guild_id;index"`
+	ChannelID string `gorm:"column:channel_id"`
+	CreatorID string `gorm:"column:creator_id"`
+	Username  string `gorm:"column:username"`
+	PostID    string `gorm:"column:post_id"`
+	Excerpt   string `gorm:"column:excerpt"`
+	CreatedAt int64  `gorm:"column:created_at"`
+}
+
+func (DigestItem) TableName() string {
+	return "digest_items"
+}
@@ -8,6 +8,7 @@ type MonitoredUser struct {
 	GuildID                 string `gorm:"primaryKey;column:guild_id"`
 	UserID                  string `gorm:"primaryKey;column:user_id"`
 	Username                string `gorm:"column:username"`
+	DisplayName             string `gorm:"column:display_name"`
 	NotificationChannel     string `gorm:"column:notification_channel"`
 	PostNotificationChannel string `gorm:"column:post_notification_channel"`
 	LiveNotificationChannel string `gorm:"column:live_notification_channel"`
@@ -19,8 +20,22 @@ type MonitoredUser struct {
 	LiveImageURL            string `gorm:"column:live_image_url"`
 	PostsEnabled            bool   `gorm:"column:posts_enabled"`
 	LiveEnabled             bool   `gorm:"column:live_enabled"`
+	ProfileEnabled          bool   `gorm:"column:profile_enabled"`
 	LiveMentionRole         string `gorm:"column:live_mention_role"`
 	PostMentionRole         string `gorm:"column:post_mention_role"`
+	SubscriberRoleID        string `gorm:"column:subscriber_role_id"`
+	// PostDelivery is "immediate" or "digest"; empty follows the guild's digest setting.
+	PostDelivery string `gorm:"column:post_delivery"`
+	// MentionCooldownMinutes limits how often notifications for this creator ping; 0 pings every time.
+	MentionCooldownMinutes int   `gorm:"column:mention_cooldown_minutes"`
+	LastMentionAt          int64 `gorm:"column:last_mention_at"`
+	// BurstWindowMinutes collapses posts made within this window of the first one into a single message.
+	BurstWindowMinutes int `gorm:"column:burst_window_minutes"`
+	// PostThreadID and LiveThreadID are the creator's threads when notifications go to a forum channel.
+	PostThreadID string `gorm:"column:post_thread_id"`
+	LiveThreadID string `gorm:"column:live_thread_id"`
+	// LiveEventID is the scheduled event of the creator's ongoing stream; it is cleared when the stream ends.
+	LiveEventID string `gorm:"column:live_event_id"`
 }
 
 type GuildSubscription struct {
@@ -97,3 +112,58 @@ type SchemaVersion struct {
 func (MonitoredUser) TableName() string {
 	return "monitored_users"
 }
+
+// PendingAdd is an /add waiting for the user to confirm adding a creator for live
+// notifications only because their timeline is not accessible.
+type PendingAdd struct {
+	ID               string `gorm:"primaryKey;column:id"`
+	GuildID          string `gorm:"column:guild_id"`
+	RequesterID      string `gorm:"column:requester_id"`
+	UserID           string `gorm:"column:user_id"`
+	Username         string `gorm:"column:username"`
+	DisplayName      string `gorm:"column:display_name"`
+	AvatarLocation   string `gorm:"column:avatar_location"`
+	ChannelID        string `gorm:"column:channel_id"`
+	MentionRole      string `gorm:"column:mention_role"`
+	PreviousSettings string `gorm:"column:previous_settings"`
+	ExpiresAt        int64  `gorm:"column:expires_at;index"`
+}
+
+func (PendingAdd) TableName() string {
+	return "pending_adds"
+}
+
+// DirectSubscription is a personal subscription of a Discord user to a creator, notified
+// in direct messages instead of a guild channel.
+type DirectSubscription struct {
+	DiscordUserID   string `gorm:"primaryKey;column:discord_user_id"`
+	CreatorID       string `gorm:"primaryKey;column:creator_id"`
+	Username        string `gorm:"column:username"`
+	AvatarLocation  string `gorm:"column:avatar_location"`
+	PostsEnabled    bool   `gorm:"column:posts_enabled"`
+	LiveEnabled     bool   `gorm:"column:live_enabled"`
+	LastPostID      string `gorm:"column:last_post_id"`
+	LastStreamStart int64  `gorm:"column:last_stream_start"`
+	// Paused is set when the user's DMs are closed; following again resumes delivery.
+	Paused    bool  `gorm:"column:paused"`
+	CreatedAt int64 `gorm:"column:created_at"`
+}
+
+// PostBurst tracks the post notification that later posts of a burst are collapsed into.
+type PostBurst struct {
+	GuildID   string `gorm:"primaryKey;column:guild_id"`
+	UserID    string `gorm:"primaryKey;column:user_id"`
+	ChannelID string `gorm:"column:channel_id"`
+	MessageID string `gorm:"column:message_id"`
+	// PostIDs lists the collapsed posts, newest first, separated by commas.
+	PostIDs   string `gorm:"column:post_ids"`
+	StartedAt int64  `gorm:"column:started_at"`
+}
+
+func (PostBurst) TableName() string {
+	return "post_bursts"
+}
+
+func (DirectSubscription) TableName() string {
+	return "direct_subscriptions"
+}